@@ -0,0 +1,55 @@
+// Package afalg computes POLYVAL with the Linux kernel's crypto
+// API through AF_ALG sockets.
+//
+// It is much slower than package polyval for short inputs,
+// since every call is a system call. It is meant for offloading
+// to kernel drivers that userspace cannot use directly and for
+// cross-checking package polyval against an independent
+// implementation in integration tests.
+//
+// The kernel must have been built with CONFIG_CRYPTO_USER_API_HASH
+// and CONFIG_CRYPTO_POLYVAL. On other platforms, and on kernels
+// without them, New returns an error that wraps ErrUnsupported.
+package afalg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ericlagergren/subtle"
+)
+
+// Size is the size in bytes of a POLYVAL checksum.
+const Size = 16
+
+// ErrUnsupported is returned when the kernel does not provide
+// POLYVAL over AF_ALG.
+var ErrUnsupported = errors.New("afalg: POLYVAL is not supported")
+
+// Sum returns the POLYVAL hash of data computed by the kernel.
+//
+// len(data) must be a multiple of 16.
+func Sum(key, data []byte) ([Size]byte, error) {
+	var sum [Size]byte
+	p, err := New(key)
+	if err != nil {
+		return sum, err
+	}
+	defer p.Close()
+	if err := p.Update(data); err != nil {
+		return sum, err
+	}
+	_, err = p.Sum(sum[:0])
+	return sum, err
+}
+
+// checkKey returns an error if key is not a valid POLYVAL key.
+func checkKey(key []byte) error {
+	if len(key) != 16 {
+		return fmt.Errorf("afalg: invalid key size: %d", len(key))
+	}
+	if subtle.ConstantTimeBigEndianZero(key) == 1 {
+		return errors.New("afalg: the zero key is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+//go:build linux
+
+package afalg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ericlagergren/subtle"
+	"golang.org/x/sys/unix"
+)
+
+// Polyval is POLYVAL computed by the kernel.
+//
+// Like polyval.Polyval, it only accepts full blocks. Unlike
+// polyval.Polyval, it holds file descriptors and must be closed
+// with Close.
+type Polyval struct {
+	// tfm is the socket bound to the algorithm and holding
+	// the key.
+	tfm int
+	// op is the socket holding the running state.
+	op int
+}
+
+// New creates a Polyval.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func New(key []byte) (*Polyval, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	tfm, err := unix.Socket(unix.AF_ALG, unix.SOCK_SEQPACKET|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	err = unix.Bind(tfm, &unix.SockaddrALG{Type: "hash", Name: "polyval"})
+	if err != nil {
+		unix.Close(tfm)
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	err = unix.SetsockoptString(tfm, unix.SOL_ALG, unix.ALG_SET_KEY, string(key))
+	if err != nil {
+		unix.Close(tfm)
+		return nil, fmt.Errorf("afalg: unable to set key: %w", err)
+	}
+	op, err := accept(tfm)
+	if err != nil {
+		unix.Close(tfm)
+		return nil, err
+	}
+	return &Polyval{tfm: tfm, op: op}, nil
+}
+
+// accept returns a new operation socket for fd.
+//
+// unix.Accept cannot be used because the kernel rejects AF_ALG
+// accepts that ask for the peer's address.
+func accept(fd int) (int, error) {
+	nfd, _, errno := unix.Syscall6(unix.SYS_ACCEPT4,
+		uintptr(fd), 0, 0, unix.SOCK_CLOEXEC, 0, 0)
+	if errno != 0 {
+		return -1, fmt.Errorf("afalg: accept: %w", errno)
+	}
+	return int(nfd), nil
+}
+
+// Size returns the size of a POLYVAL digest.
+func (p *Polyval) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (p *Polyval) BlockSize() int {
+	return 16
+}
+
+// Reset sets the hash to its original state.
+func (p *Polyval) Reset() error {
+	op, err := accept(p.tfm)
+	if err != nil {
+		return err
+	}
+	unix.Close(p.op)
+	p.op = op
+	return nil
+}
+
+// Update writes one or more blocks to the running hash.
+//
+// If len(block) is not divisible by BlockSize, Update will panic.
+func (p *Polyval) Update(blocks []byte) error {
+	if len(blocks)%16 != 0 {
+		panic("afalg: invalid input length")
+	}
+	for len(blocks) > 0 {
+		// MSG_MORE keeps the kernel from finalizing the hash.
+		n, err := unix.SendmsgN(p.op, blocks, nil, nil, unix.MSG_MORE)
+		if err != nil {
+			return fmt.Errorf("afalg: write: %w", err)
+		}
+		blocks = blocks[n:]
+	}
+	return nil
+}
+
+// Sum appends the current hash to b and returns the resulting
+// slice.
+//
+// It does not change the underlying hash state.
+func (p *Polyval) Sum(b []byte) ([]byte, error) {
+	// Reading from the operation socket would finalize it.
+	// Accepting on it instead clones the running state into a
+	// new socket, which is finalized instead.
+	fd, err := accept(p.op)
+	if err != nil {
+		return b, err
+	}
+	defer unix.Close(fd)
+
+	ret, out := subtle.SliceForAppend(b, Size)
+	n, err := unix.Read(fd, out)
+	if err != nil {
+		return b, fmt.Errorf("afalg: read: %w", err)
+	}
+	if n != Size {
+		return b, errors.New("afalg: short read")
+	}
+	return ret, nil
+}
+
+// Close releases the file descriptors held by p.
+func (p *Polyval) Close() error {
+	err1 := unix.Close(p.op)
+	err2 := unix.Close(p.tfm)
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
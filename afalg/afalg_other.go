@@ -0,0 +1,46 @@
+//go:build !linux
+
+package afalg
+
+// Polyval is POLYVAL computed by the kernel.
+//
+// It is only supported on Linux.
+type Polyval struct{}
+
+// New returns an error that wraps ErrUnsupported.
+func New(key []byte) (*Polyval, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	return nil, ErrUnsupported
+}
+
+// Size returns the size of a POLYVAL digest.
+func (p *Polyval) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (p *Polyval) BlockSize() int {
+	return 16
+}
+
+// Reset returns ErrUnsupported.
+func (p *Polyval) Reset() error {
+	return ErrUnsupported
+}
+
+// Update returns ErrUnsupported.
+func (p *Polyval) Update(blocks []byte) error {
+	return ErrUnsupported
+}
+
+// Sum returns ErrUnsupported.
+func (p *Polyval) Sum(b []byte) ([]byte, error) {
+	return b, ErrUnsupported
+}
+
+// Close returns ErrUnsupported.
+func (p *Polyval) Close() error {
+	return ErrUnsupported
+}
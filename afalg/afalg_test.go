@@ -0,0 +1,89 @@
+package afalg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval"
+)
+
+// newOrSkip is like New, but skips the test if the kernel does
+// not support POLYVAL.
+func newOrSkip(t *testing.T, key []byte) *Polyval {
+	p, err := New(key)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		p.Close()
+	})
+	return p
+}
+
+// TestMatchesPolyval tests that the kernel's POLYVAL matches
+// package polyval.
+func TestMatchesPolyval(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	buf := make([]byte, 16*300)
+	rng.Read(buf)
+
+	p := newOrSkip(t, key)
+	for _, nblocks := range []int{0, 1, 2, 15, 16, 17, 100, 300} {
+		data := buf[:16*nblocks]
+		want := polyval.Sum(key, data)
+
+		got, err := Sum(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("%d blocks: expected %x, got %x", nblocks, want, got)
+		}
+
+		if err := p.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		for b := data; len(b) > 0; b = b[16:] {
+			if err := p.Update(b[:16]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Sum must not change the state.
+		for i := 0; i < 2; i++ {
+			sum, err := p.Sum(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(sum, want[:]) {
+				t.Fatalf("%d blocks, #%d: expected %x, got %x",
+					nblocks, i, want, sum)
+			}
+		}
+	}
+}
+
+// TestInvalidKey tests that New rejects invalid keys on every
+// platform.
+func TestInvalidKey(t *testing.T) {
+	for _, key := range [][]byte{
+		nil,
+		make([]byte, 15),
+		make([]byte, 16),
+	} {
+		_, err := New(key)
+		if err == nil || errors.Is(err, ErrUnsupported) {
+			t.Fatalf("%x: expected a key error, got %v", key, err)
+		}
+	}
+}
@@ -1,32 +1,50 @@
 package main
 
 import (
+	"fmt"
+
 	. "github.com/mmcloughlin/avo/build"
 	// . "github.com/mmcloughlin/avo/gotypes"
 	. "github.com/mmcloughlin/avo/operand"
 	. "github.com/mmcloughlin/avo/reg"
 )
 
-//go:generate go run asm.go -out ../polyval_amd64.s -stubs ../stub_amd64.go -pkg polyval
+//go:generate go run asm.go -out ../internal/field/field_amd64.s -stubs ../internal/field/stub_amd64.go -pkg field
+
+var mask, bswapMask Mem
 
-var mask Mem
+// prefetchDistance is how far ahead of the current stride, in
+// bytes, the wide loops prefetch the input.
+//
+// Large inputs are partially memory bound, so this hides some
+// of the latency of streaming them in. It should be a multiple
+// of 64.
+const prefetchDistance = 4096
 
 func main() {
-	Package("github.com/ericlagergren/polyval")
-	ConstraintExpr("gc,!purego")
+	Package("github.com/ericlagergren/polyval/internal/field")
+	ConstraintExpr("gc,!purego,!noasm,!tinygo")
 
 	mask = GLOBL("polymask", RODATA|NOPTR)
 	DATA(0, U64(0xc200000000000000))
 	DATA(8, U64(0xc200000000000000))
 
+	bswapMask = GLOBL("bswapmask", RODATA|NOPTR)
+	DATA(0, U64(0x08090a0b0c0d0e0f))
+	DATA(8, U64(0x0001020304050607))
+
 	declarePolymul()
 	declarePolymulBlocks()
+	declarePolymulAVX()
+	declarePolymulBlocksAVX()
+	declarePolymulBlocksAVX512()
+	declareReverseBlocks()
 
 	Generate()
 }
 
 // The following assembly is a literal translation of
-// polymulGeneric and polyBlocksGeneric. See those functions
+// MulGeneric and MulBlocksGeneric. See those functions
 // for more information on the algorithms.
 //
 // For a slightly easier to read example using intrinsics
@@ -143,7 +161,7 @@ func loadMask() VecVirtual {
 }
 
 func declarePolymul() {
-	TEXT("polymulAsm", NOSPLIT, "func(acc, key *fieldElement)")
+	TEXT("polymulAsm", NOSPLIT, "func(acc, key *Element)")
 	Pragma("noescape")
 
 	acc := Load(Param("acc"), GP64())
@@ -161,7 +179,7 @@ func declarePolymul() {
 }
 
 func declarePolymulBlocks() {
-	TEXT("polymulBlocksAsm", NOSPLIT, "func(acc *fieldElement, pow *[8]fieldElement, input *byte, nblocks int)")
+	TEXT("polymulBlocksAsm", NOSPLIT, "func(acc *Element, pow *[16]Element, input *byte, nblocks int)")
 	Pragma("noescape")
 
 	acc := Mem{Base: Load(Param("acc"), GP64())}
@@ -174,64 +192,553 @@ func declarePolymulBlocks() {
 	d := XMM()
 	MOVOU(acc, d)
 
-	nsingle := GP64()
-	MOVQ(nblocks, nsingle)
-	ANDQ(U8(7), nsingle)
-	JZ(LabelRef("initWideLoop"))
+	mul := func(n int) {
+		mulBlocks(mask, d, pow, input, n)
+	}
+	mulShort(nblocks, mul)
+	mulSmall(nblocks, input, mul)
+	mulTail(nblocks, input, mul)
+
+	// Wide loop handles full 16-block strides.
+	Label("initWideLoop")
+	nwide := GP64()
+	MOVQ(nblocks, nwide)
+	SHRQ(U8(4), nwide)
+	JZ(LabelRef("done"))
+
+	Label("wideLoop")
+	prefetch(input, 16)
+	mulBlocks(mask, d, pow, input, 16)
+	ADDQ(U32(16*16), input.Base)
+	SUBQ(U8(1), nwide)
+	JNZ(LabelRef("wideLoop"))
+
+	Label("done")
+	MOVOU(d, acc)
+
+	RET()
+}
+
+// mulShort writes inputs of up to eight blocks with a single
+// call to mul, which writes n blocks, and then jumps to the
+// "done" label. Longer inputs fall through.
+//
+// Short messages are common and this avoids the remainder
+// handling in mulSmall and mulTail, which would split five to
+// seven blocks into two reductions.
+func mulShort(nblocks Register, mul func(n int)) {
+	CMPQ(nblocks, U8(8))
+	JA(LabelRef("long"))
+	JE(LabelRef("short8"))
+	CMPQ(nblocks, U8(4))
+	JA(LabelRef("short5to7"))
+	JE(LabelRef("short4"))
+	CMPQ(nblocks, U8(2))
+	JB(LabelRef("short1"))
+	JE(LabelRef("short2"))
+	for _, n := range []int{3, 2, 1, 4, 8} {
+		if n != 3 {
+			Label(fmt.Sprintf("short%d", n))
+		}
+		mul(n)
+		JMP(LabelRef("done"))
+	}
+	Label("short5to7")
+	CMPQ(nblocks, U8(6))
+	JB(LabelRef("short5"))
+	JE(LabelRef("short6"))
+	for _, n := range []int{7, 5, 6} {
+		Label(fmt.Sprintf("short%d", n))
+		mul(n)
+		JMP(LabelRef("done"))
+	}
+	Label("long")
+}
+
+// mulSmall writes the first nblocks%4 blocks using mul, which
+// writes n blocks, and then falls through to the "tail4" label.
+//
+// The blocks are written with a single call to mul, so inputs
+// shorter than four blocks only need one reduction.
+func mulSmall(nblocks Register, input Mem, mul func(n int)) {
+	nsmall := GP64()
+	MOVQ(nblocks, nsmall)
+	ANDQ(U8(3), nsmall)
+	JZ(LabelRef("tail4"))
+	CMPQ(nsmall, U8(2))
+	JB(LabelRef("small1"))
+	JE(LabelRef("small2"))
+	for _, n := range []int{3, 1, 2} {
+		if n != 3 {
+			Label(fmt.Sprintf("small%d", n))
+		}
+		mul(n)
+		ADDQ(U8(n*16), input.Base)
+		if n != 2 {
+			JMP(LabelRef("tail4"))
+		}
+	}
+	Label("tail4")
+}
+
+// mulTail writes the remaining nblocks%16 blocks that do not
+// fill a full stride using mul, which writes n blocks, and then
+// falls through to the "initWideLoop" label.
+//
+// Four and eight blocks are each handled with a single call to
+// mul, so the remainder needs at most three reductions instead
+// of one per block.
+func mulTail(nblocks Register, input Mem, mul func(n int)) {
+	for _, n := range []int{4, 8} {
+		next := "initWideLoop"
+		if n == 4 {
+			next = "tail8"
+		}
+		TESTQ(U32(n), nblocks)
+		JZ(LabelRef(next))
+		mul(n)
+		ADDQ(U8(n*16), input.Base)
+		if n == 4 {
+			Label(next)
+		}
+	}
+}
+
+// mulBlocks writes n blocks to d using the last n powers of the
+// hash key in pow.
+//
+// n must be at most eight or exactly 16. 16 blocks are
+// split into two independent sets of accumulators to shorten
+// the dependency chains.
+func mulBlocks(mask, d VecVirtual, pow, input Mem, n int) {
+	var sets [2][3]VecVirtual // (H, L, M)
+	for _, i := range blockOrder(n) {
+		Commentf("Block %d", i)
+		msg, key := XMM(), XMM()
+		MOVOU(input.Offset(i*16), msg)
+		MOVOU(pow.Offset((16-n+i)*16), key)
+		if i == 0 {
+			// Fold in accumulator
+			PXOR(d, msg)
+		}
+		h, l, m := karatsuba1(msg, key)
+		acc := &sets[i/8]
+		if acc[0] == nil {
+			acc[0], acc[1], acc[2] = h, l, m
+		} else {
+			PXOR(h, acc[0])
+			PXOR(l, acc[1])
+			PXOR(m, acc[2])
+		}
+	}
+	H, L, M := sets[0][0], sets[0][1], sets[0][2]
+	if n == 16 {
+		Comment("Combine accumulators")
+		PXOR(sets[1][0], H)
+		PXOR(sets[1][1], L)
+		PXOR(sets[1][2], M)
+	}
+	x01, x23 := karatsuba2(H, L, M)
+	reduce(mask, d, x01, x23)
+}
+
+// blockOrder returns the order in which mulBlocks processes
+// n blocks.
+//
+// The two sets of accumulators are interleaved and block zero,
+// which depends on the accumulator, is last.
+func blockOrder(n int) []int {
+	var order []int
+	for i := min(n, 8) - 1; i >= 0; i-- {
+		if n == 16 {
+			order = append(order, i+8)
+		}
+		order = append(order, i)
+	}
+	return order
+}
+
+// prefetch prefetches each cache line of an n-block stride
+// prefetchDistance bytes ahead of input.
+func prefetch(input Mem, n int) {
+	for i := 0; i < n*16; i += 64 {
+		PREFETCHT0(input.Offset(prefetchDistance + i))
+	}
+}
+
+// The following functions are VEX-encoded versions of
+// karatsuba1, karatsuba2, and reduce.
+//
+// They must be used instead of the SSE versions when the upper
+// bits of the vector registers are in use, since legacy SSE
+// instructions leave the upper bits unmodified.
+
+// karatsuba1VEX is like karatsuba1, except it does not clobber
+// x and it works on each 128-bit lane of x and y.
+func karatsuba1VEX(x, y VecVirtual) (H, L, M VecVirtual) {
+	Comment("Karatsuba 1")
+	H, L, M = newVec(x), newVec(x), newVec(x)
+	t0 := newVec(x)
+	VPSHUFD(U8(0xEE), x, t0)
+	vpxor(x, t0, t0)
+	VPSHUFD(U8(0xEE), y, M)
+	vpxor(y, M, M)
+	VPCLMULQDQ(U8(0x00), t0, M, M)
+	VPCLMULQDQ(U8(0x11), y, x, H)
+	VPCLMULQDQ(U8(0x00), y, x, L)
+	return H, L, M
+}
+
+// karatsuba2VEX is like karatsuba2.
+func karatsuba2VEX(H, L, M VecVirtual) (x01, x23 VecVirtual) {
+	Comment("Karatsuba 2")
+	t1 := XMM() // temp
+	t2 := XMM() // temp
+	VSHUFPS(U8(0x4E), H, L, t1)
+	VPXOR(L, H, t2)
+	VPXOR(t1, t2, t2)
+	VPXOR(M, t2, t2)
+	VMOVHLPS(t2, H, H)    // x23
+	VPUNPCKLQDQ(t2, L, L) // x01
+	return L, H
+}
+
+// reduceVEX is like reduce.
+func reduceVEX(mask, v, x01, x23 VecVirtual) {
+	Comment("Montgomery reduce")
+	VPCLMULQDQ(U8(0x00), x01, mask, v) // (A1, A0) = X0 * poly
+	VPSHUFD(U8(0x4E), v, v)            // (A1, A0) = (A0, A1)
+	VPXOR(x01, v, v)                   // (B1, B0) = (X0^A1, X1^A0)
+	VPXOR(v, x23, x23)                 // (D1, D0) = (B1^X3, B0^X2)
+	VPCLMULQDQ(U8(0x11), mask, v, v)   // (C1, C0) = B0 * poly
+	VPXOR(x23, v, v)                   // [D1^X3 : D0^X2]
+}
+
+// polymulVEX is like polymul, except it does not clobber x.
+func polymulVEX(mask, z, x, y VecVirtual) {
+	H, L, M := karatsuba1VEX(x, y)
+	x01, x23 := karatsuba2VEX(H, L, M)
+	reduceVEX(mask, z, x01, x23)
+}
+
+func loadMaskVEX() VecVirtual {
+	m := XMM()
+	VMOVDQU(mask, m)
+	return m
+}
+
+// fold4 XORs the four 128-bit lanes of z and the 128-bit w
+// together and returns the result.
+func fold4(z, w VecVirtual) VecVirtual {
+	y := YMM()
+	VEXTRACTI64X4(U8(1), z, y)
+	VPXOR(z.AsY(), y, y)
+	x := XMM()
+	VEXTRACTI128(U8(1), y, x)
+	vpxor3(w, asX(y), x)
+	return x
+}
+
+// The following functions are versions of karatsuba2VEX and
+// reduceVEX that use VPTERNLOGD to compute
+// three-way XORs. They require AVX512VL.
+
+// vpxor3 sets z = x^y^z.
+func vpxor3(x, y, z VecVirtual) {
+	VPTERNLOGD(U8(0x96), x, y, z)
+}
+
+// karatsuba2AVX512 is like karatsuba2VEX.
+func karatsuba2AVX512(H, L, M VecVirtual) (x01, x23 VecVirtual) {
+	Comment("Karatsuba 2")
+	t1 := XMM() // temp
+	t2 := XMM() // temp
+	VSHUFPS(U8(0x4E), H, L, t1)
+	VPXOR(L, H, t2)
+	vpxor3(M, t1, t2)
+	VMOVHLPS(t2, H, H)    // x23
+	VPUNPCKLQDQ(t2, L, L) // x01
+	return L, H
+}
+
+// reduceAVX512 is like reduceVEX.
+func reduceAVX512(mask, v, x01, x23 VecVirtual) {
+	Comment("Montgomery reduce")
+	VPCLMULQDQ(U8(0x00), x01, mask, v) // (A1, A0) = X0 * poly
+	VPSHUFD(U8(0x4E), v, v)            // (A1, A0) = (A0, A1)
+	VPXOR(x01, v, v)                   // (B1, B0) = (X0^A1, X1^A0)
+	VPCLMULQDQ(U8(0x11), mask, v, x01) // (C1, C0) = B0 * poly
+	vpxor3(x23, x01, v)                // [D1^X3 : D0^X2]
+}
+
+// newVec returns a new vector register the same size as v.
+func newVec(v VecVirtual) VecVirtual {
+	switch v.Size() {
+	case 16:
+		return XMM()
+	case 32:
+		return YMM()
+	default:
+		return ZMM()
+	}
+}
+
+// asX returns the low 128 bits of v.
+func asX(v VecVirtual) VecVirtual {
+	return v.AsX().(VecVirtual)
+}
+
+// vpxor uses VPXORQ for 512-bit vectors, which do not have
+// a VPXOR encoding.
+func vpxor(x, y, z VecVirtual) {
+	if z.Size() == 64 {
+		VPXORQ(x, y, z)
+	} else {
+		VPXOR(x, y, z)
+	}
+}
+
+func declarePolymulAVX() {
+	TEXT("polymulAVX", NOSPLIT, "func(acc, key *Element)")
+	Pragma("noescape")
 
-	// Single loop handles any excess blocks if nblocks is not
-	// a multiple of the stride.
-	Label("initSingleLoop")
-	key := XMM()
-	MOVOU(pow.Offset(7*16), key)
+	acc := Load(Param("acc"), GP64())
+	key := Load(Param("key"), GP64())
 
-	Label("singleLoop")
-	msg := XMM()
-	MOVOU(input, msg)
-	PXOR(d, msg)
-	polymul(mask, d, msg, key)
+	x, y := XMM(), XMM()
+	VMOVDQU(Mem{Base: acc}, x)
+	VMOVDQU(Mem{Base: key}, y)
 
-	ADDQ(U8(16), input.Base)
-	SUBQ(U8(1), nsingle)
-	JNZ(LabelRef("singleLoop"))
+	z := XMM()
+	polymulVEX(loadMaskVEX(), z, x, y)
+	VMOVDQU(z, Mem{Base: acc})
 
-	// Wide loop handles full 8-block strides.
+	RET()
+}
+
+func declarePolymulBlocksAVX() {
+	TEXT("polymulBlocksAVX", NOSPLIT, "func(acc *Element, pow *[16]Element, input *byte, nblocks int)")
+	Pragma("noescape")
+
+	acc := Mem{Base: Load(Param("acc"), GP64())}
+	pow := Mem{Base: Load(Param("pow"), GP64())}
+	input := Mem{Base: Load(Param("input"), GP64())}
+	nblocks := Load(Param("nblocks"), GP64())
+
+	mask := loadMaskVEX()
+
+	d := XMM()
+	VMOVDQU(acc, d)
+
+	mul := func(n int) {
+		mulBlocksVEX(mask, d, pow, input, n)
+	}
+	mulShort(nblocks, mul)
+	mulSmall(nblocks, input, mul)
+	mulTail(nblocks, input, mul)
+
+	// Wide loop handles full 16-block strides.
 	Label("initWideLoop")
 	nwide := GP64()
 	MOVQ(nblocks, nwide)
-	SHRQ(U8(3), nwide)
+	SHRQ(U8(4), nwide)
 	JZ(LabelRef("done"))
 
 	Label("wideLoop")
-	{
-		H, M, L := XMM(), XMM(), XMM()
-		PXOR(H, H)
-		PXOR(L, L)
-		PXOR(M, M)
-		for i := 7; i >= 0; i-- {
-			Commentf("Block %d", i)
-			msg, key := XMM(), XMM()
-			MOVOU(input.Offset(i*16), msg)
-			MOVOU(pow.Offset(i*16), key)
-			if i == 0 {
-				// Fold in accumulator
-				PXOR(d, msg)
-			}
-			h, l, m := karatsuba1(msg, key)
-			PXOR(h, H)
-			PXOR(l, L)
-			PXOR(m, M)
+	prefetch(input, 16)
+	mulBlocksVEX(mask, d, pow, input, 16)
+	ADDQ(U32(16*16), input.Base)
+	SUBQ(U8(1), nwide)
+	JNZ(LabelRef("wideLoop"))
+
+	Label("done")
+	VMOVDQU(d, acc)
+
+	RET()
+}
+
+// mulBlocksVEX is like mulBlocks.
+func mulBlocksVEX(mask, d VecVirtual, pow, input Mem, n int) {
+	var sets [2][3]VecVirtual // (H, L, M)
+	for _, i := range blockOrder(n) {
+		Commentf("Block %d", i)
+		msg, key := XMM(), XMM()
+		VMOVDQU(input.Offset(i*16), msg)
+		VMOVDQU(pow.Offset((16-n+i)*16), key)
+		if i == 0 {
+			// Fold in accumulator
+			VPXOR(d, msg, msg)
+		}
+		h, l, m := karatsuba1VEX(msg, key)
+		acc := &sets[i/8]
+		if acc[0] == nil {
+			acc[0], acc[1], acc[2] = h, l, m
+		} else {
+			VPXOR(h, acc[0], acc[0])
+			VPXOR(l, acc[1], acc[1])
+			VPXOR(m, acc[2], acc[2])
 		}
-		x01, x23 := karatsuba2(H, L, M)
-		reduce(mask, d, x01, x23)
+	}
+	H, L, M := sets[0][0], sets[0][1], sets[0][2]
+	if n == 16 {
+		Comment("Combine accumulators")
+		VPXOR(sets[1][0], H, H)
+		VPXOR(sets[1][1], L, L)
+		VPXOR(sets[1][2], M, M)
+	}
+	x01, x23 := karatsuba2VEX(H, L, M)
+	reduceVEX(mask, d, x01, x23)
+}
+
+func declarePolymulBlocksAVX512() {
+	TEXT("polymulBlocksAVX512", NOSPLIT, "func(acc *Element, pow *[16]Element, input *byte, nblocks int)")
+	Pragma("noescape")
 
-		ADDQ(U8(8*16), input.Base)
-		SUBQ(U8(1), nwide)
-		JNZ(LabelRef("wideLoop"))
+	acc := Mem{Base: Load(Param("acc"), GP64())}
+	pow := Mem{Base: Load(Param("pow"), GP64())}
+	input := Mem{Base: Load(Param("input"), GP64())}
+	nblocks := Load(Param("nblocks"), GP64())
+
+	mask := loadMaskVEX()
+
+	d := ZMM()
+	VMOVDQU(acc, d.AsX())
+
+	// Fewer than four blocks do not fill a 512-bit vector, so
+	// use 128-bit vectors and skip loading the powers.
+	mulSmall(nblocks, input, func(n int) {
+		mulBlocksVEX(mask, asX(d), pow, input, n)
+	})
+	CMPQ(nblocks, U8(4))
+	JB(LabelRef("done"))
+
+	// Four powers per 512-bit vector. They are used by every
+	// iteration.
+	var keys [4]VecVirtual
+	for i := range keys {
+		keys[i] = ZMM()
+		VMOVDQU64(pow.Offset(i*4*16), keys[i])
 	}
 
+	mulTail(nblocks, input, func(n int) {
+		mulBlocksAVX512(mask, d, keys, input, n)
+	})
+
+	// Wide loop handles full 16-block strides, four blocks per
+	// 512-bit vector.
+	Label("initWideLoop")
+	nwide := GP64()
+	MOVQ(nblocks, nwide)
+	SHRQ(U8(4), nwide)
+	JZ(LabelRef("done"))
+
+	Label("wideLoop")
+	prefetch(input, 16)
+	mulBlocksAVX512(mask, d, keys, input, 16)
+	ADDQ(U32(16*16), input.Base)
+	SUBQ(U8(1), nwide)
+	JNZ(LabelRef("wideLoop"))
+
 	Label("done")
-	MOVOU(d, acc)
+	VMOVDQU(d.AsX(), acc)
+	VZEROUPPER()
+
+	RET()
+}
+
+// mulBlocksAVX512 is like mulBlocks, except that it processes
+// four blocks at a time.
+//
+// keys contains pow[0:4], pow[4:8], and so on.
+func mulBlocksAVX512(mask, d VecVirtual, keys [4]VecVirtual, input Mem, n int) {
+	ks := keys[4-n/4:]
+	var sets [2][3]VecVirtual // (H, L, M)
+	for i := 0; i < n/4; i++ {
+		Commentf("Blocks %d-%d", i*4, i*4+3)
+		msg := ZMM()
+		VMOVDQU64(input.Offset(i*4*16), msg)
+		h, l, m := karatsuba1VEX(msg, ks[i])
+		acc := &sets[i%2]
+		if i > 0 && i == n/4-1 {
+			// Fold the last product directly into the
+			// first set.
+			acc = &sets[0]
+		}
+		switch {
+		case acc[0] == nil:
+			acc[0], acc[1], acc[2] = h, l, m
+		case sets[1][0] != nil && i == n/4-1:
+			// Fold the second set in, too.
+			vpxor3(h, sets[1][0], acc[0])
+			vpxor3(l, sets[1][1], acc[1])
+			vpxor3(m, sets[1][2], acc[2])
+		default:
+			VPXORQ(h, acc[0], acc[0])
+			VPXORQ(l, acc[1], acc[1])
+			VPXORQ(m, acc[2], acc[2])
+		}
+	}
+	H, L, M := sets[0][0], sets[0][1], sets[0][2]
+
+	// Multiply the accumulator by the first power separately
+	// instead of folding it into block 0 so that only one
+	// 128-bit multiplication and the reduction depend on the
+	// previous iteration.
+	Comment("Accumulator")
+	h, l, m := karatsuba1VEX(asX(d), asX(ks[0]))
+
+	Comment("Fold lanes")
+	Hx, Lx, Mx := fold4(H, h), fold4(L, l), fold4(M, m)
+
+	x01, x23 := karatsuba2AVX512(Hx, Lx, Mx)
+	reduceAVX512(mask, asX(d), x01, x23)
+}
+
+// declareReverseBlocks declares reverseBlocksAsm, which reverses
+// the bytes of each 16-byte block.
+//
+// It uses PSHUFB, which every CPU with PCLMULQDQ supports.
+func declareReverseBlocks() {
+	TEXT("reverseBlocksAsm", NOSPLIT, "func(dst, src *byte, nblocks int)")
+	Pragma("noescape")
 
+	dst := Mem{Base: Load(Param("dst"), GP64())}
+	src := Mem{Base: Load(Param("src"), GP64())}
+	nblocks := Load(Param("nblocks"), GP64())
+
+	m := XMM()
+	MOVOU(bswapMask, m)
+
+	Comment("Four blocks at a time")
+	Label("loop4")
+	CMPQ(nblocks, U8(4))
+	JB(LabelRef("loop1"))
+	var x [4]VecVirtual
+	for i := range x {
+		x[i] = XMM()
+		MOVOU(src.Offset(i*16), x[i])
+		PSHUFB(m, x[i])
+	}
+	for i := range x {
+		MOVOU(x[i], dst.Offset(i*16))
+	}
+	ADDQ(U8(4*16), src.Base)
+	ADDQ(U8(4*16), dst.Base)
+	SUBQ(U8(4), nblocks)
+	JMP(LabelRef("loop4"))
+
+	Label("loop1")
+	TESTQ(nblocks, nblocks)
+	JZ(LabelRef("done"))
+	y := XMM()
+	MOVOU(src, y)
+	PSHUFB(m, y)
+	MOVOU(y, dst)
+	ADDQ(U8(16), src.Base)
+	ADDQ(U8(16), dst.Base)
+	SUBQ(U8(1), nblocks)
+	JMP(LabelRef("loop1"))
+
+	Label("done")
 	RET()
 }
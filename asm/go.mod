@@ -2,14 +2,12 @@ module github.com/ericlagergren/polymul/asm
 
 go 1.18
 
-require github.com/mmcloughlin/avo v0.4.0
+require github.com/mmcloughlin/avo v0.6.0
 
 require (
 	github.com/ericlagergren/polyval v0.0.0-20220201125853-ee0e43c15484 // indirect
-	golang.org/x/mod v0.4.2 // indirect
-	golang.org/x/sys v0.0.0-20211030160813-b3129d9d1021 // indirect
-	golang.org/x/tools v0.1.7 // indirect
-	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+	golang.org/x/mod v0.14.0 // indirect
+	golang.org/x/tools v0.16.1 // indirect
 )
 
 replace github.com/ericlagergren/polyval => ../
@@ -3,15 +3,15 @@
 set -xeuo pipefail
 
 go run asm.go \
-	-out out/polyval_amd64.s \
+	-out out/field_amd64.s \
 	-stubs out/stub_amd64.go \
-	-pkg polyval
+	-pkg field
 gofmt -s -w out/*.go
 asmfmt -w out/*.s
-mv out/* ../
+mv out/* ../internal/field/
 export CGO_ENABLED=1
 export GOARCH=amd64
-go test github.com/ericlagergren/polyval \
+go test github.com/ericlagergren/polyval/... \
 	-v \
 	-vet all \
 	-failfast \
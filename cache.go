@@ -0,0 +1,114 @@
+package polyval
+
+import (
+	"container/list"
+	"crypto/subtle"
+	"sync"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// KeyCache is a bounded cache of expanded keys.
+//
+// Initializing a Polyval computes fifteen powers of the key.
+// Programs that create many Polyvals for the same few keys can
+// share a KeyCache so that this is done once per key.
+//
+// Entries are found by a non-cryptographic fingerprint of the
+// key with a random per-process seed (see Hash64) and the full
+// key is compared before an entry is used. The cache holds
+// copies of the keys, so it must be protected like the keys
+// themselves.
+//
+// A KeyCache is safe for concurrent use.
+type KeyCache struct {
+	mu sync.Mutex
+	// max is the maximum number of entries.
+	max int
+	// entries maps fingerprints to elements of lru.
+	entries map[uint64]*list.Element
+	// lru holds *keyCacheEntry, most recently used first.
+	lru list.List
+}
+
+type keyCacheEntry struct {
+	fp  uint64
+	key [16]byte
+	pow [16]field.Element
+}
+
+// NewKeyCache creates a KeyCache that holds up to size keys.
+//
+// When the cache is full, the least recently used key is
+// evicted. If size is less than one, NewKeyCache panics.
+func NewKeyCache(size int) *KeyCache {
+	if size < 1 {
+		panic("polyval: invalid cache size")
+	}
+	return &KeyCache{
+		max:     size,
+		entries: make(map[uint64]*list.Element),
+	}
+}
+
+// New is like the package-level New, but uses the cached
+// powers of key if present.
+func (c *KeyCache) New(key []byte) (*Polyval, error) {
+	p := new(Polyval)
+	if err := c.Init(p, key); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Init is like p.Init, but uses the cached powers of key if
+// present.
+func (c *KeyCache) Init(p *Polyval, key []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	fp := Hash64(key)
+
+	c.mu.Lock()
+	if e, ok := c.entries[fp]; ok {
+		v := e.Value.(*keyCacheEntry)
+		if subtle.ConstantTimeCompare(v.key[:], key) == 1 {
+			c.lru.MoveToFront(e)
+			p.pow = v.pow
+			c.mu.Unlock()
+			p.h = p.pow[len(p.pow)-1]
+			return nil
+		}
+	}
+	c.mu.Unlock()
+
+	if err := p.Init(key); err != nil {
+		return err
+	}
+	v := &keyCacheEntry{fp: fp, pow: p.pow}
+	copy(v.key[:], key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[fp]; ok {
+		// Either another goroutine added the same key or the
+		// fingerprints collide. Either way, keep the newest.
+		e.Value = v
+		c.lru.MoveToFront(e)
+		return nil
+	}
+	c.entries[fp] = c.lru.PushFront(v)
+	if c.lru.Len() > c.max {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.entries, e.Value.(*keyCacheEntry).fp)
+	}
+	return nil
+}
+
+// Len returns the number of keys in the cache.
+func (c *KeyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
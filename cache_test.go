@@ -0,0 +1,99 @@
+package polyval
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestKeyCache tests that a Polyval initialized from a KeyCache
+// matches one initialized with New.
+func TestKeyCache(t *testing.T) {
+	runTests(t, testKeyCache)
+}
+
+func testKeyCache(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+
+	keys := make([][]byte, 10)
+	for i := range keys {
+		keys[i] = make([]byte, 16)
+		rng.Read(keys[i])
+		keys[i][0] |= 1
+	}
+	msg := make([]byte, 16*37)
+	rng.Read(msg)
+
+	const size = 4
+	c := NewKeyCache(size)
+	for i := 0; i < 1000; i++ {
+		key := keys[rng.Intn(len(keys))]
+		p, err := c.New(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Update(msg)
+		want := Sum(key, msg)
+		if got := p.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+		if n := c.Len(); n > size {
+			t.Fatalf("#%d: cache has %d entries, max %d", i, n, size)
+		}
+	}
+
+	for _, key := range [][]byte{nil, make([]byte, 15), make([]byte, 16)} {
+		if _, err := c.New(key); err == nil {
+			t.Fatalf("%x: expected an error", key)
+		}
+	}
+}
+
+// TestKeyCacheConcurrent tests that a KeyCache can be used from
+// multiple goroutines.
+func TestKeyCacheConcurrent(t *testing.T) {
+	c := NewKeyCache(2)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := make([]byte, 16)
+			for j := 0; j < 1000; j++ {
+				key[0] = byte(1 + (i+j)%3)
+				p, err := c.New(key)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				want := Sum(key, key)
+				p.Update(key)
+				if got := p.Sum(nil); !bytes.Equal(got, want[:]) {
+					t.Errorf("expected %x, got %x", want, got)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkKeyCache(b *testing.B) {
+	key := unhex("01000000000000000000000000000000")
+	c := NewKeyCache(1)
+	var p Polyval
+	b.Run("Init", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.Init(key)
+		}
+	})
+	b.Run("KeyCache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.Init(&p, key)
+		}
+	})
+}
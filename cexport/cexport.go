@@ -0,0 +1,79 @@
+//go:build cgo
+
+package main
+
+import (
+	"runtime/cgo"
+
+	"github.com/ericlagergren/polyval"
+)
+
+func main() {}
+
+// Return values. Keep in sync with polyval.h.
+const (
+	statusOK      = 0
+	statusInvalid = -1
+)
+
+// The exported functions are thin wrappers around the following
+// functions, which can be tested without cgo. They report
+// invalid arguments, including NULL pointers, which arrive as
+// nil slices, instead of panicking, since a panic would abort
+// the calling process. The exception is a handle that has
+// already been freed: cgo.Handle cannot detect it, and using
+// one is undefined.
+
+// sum writes the POLYVAL hash of data to out.
+func sum(key, data, out []byte) int {
+	if len(data)%16 != 0 || len(out) != 16 {
+		return statusInvalid
+	}
+	p, err := polyval.New(key)
+	if err != nil {
+		return statusInvalid
+	}
+	p.Update(data)
+	p.Sum(out[:0])
+	p.Wipe()
+	return statusOK
+}
+
+// newHandle returns a handle to a new Polyval, or zero if the
+// key is invalid.
+func newHandle(key []byte) cgo.Handle {
+	p, err := polyval.New(key)
+	if err != nil {
+		return 0
+	}
+	return cgo.NewHandle(p)
+}
+
+func update(h cgo.Handle, data []byte) int {
+	if h == 0 || len(data)%16 != 0 {
+		return statusInvalid
+	}
+	h.Value().(*polyval.Polyval).Update(data)
+	return statusOK
+}
+
+func finish(h cgo.Handle, out []byte) int {
+	if h == 0 || len(out) != 16 {
+		return statusInvalid
+	}
+	h.Value().(*polyval.Polyval).Sum(out[:0])
+	return statusOK
+}
+
+func reset(h cgo.Handle) {
+	if h != 0 {
+		h.Value().(*polyval.Polyval).Reset()
+	}
+}
+
+func free(h cgo.Handle) {
+	if h != 0 {
+		h.Value().(*polyval.Polyval).Wipe()
+		h.Delete()
+	}
+}
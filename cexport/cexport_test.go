@@ -0,0 +1,99 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func unhex(s string) []byte {
+	p, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// TestExports tests the functions behind the C exports using
+// the two-block test vector from RFC 8452.
+func TestExports(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262" +
+		"d1a24ddd2721d006bbe45f20d3c9f362")
+	want := unhex("f7a3b47b846119fae5b7866cf5e5b77e")
+
+	out := make([]byte, 16)
+	if s := sum(key, msg, out); s != statusOK || !bytes.Equal(out, want) {
+		t.Fatalf("sum: expected (%d, %x), got (%d, %x)", statusOK, want, s, out)
+	}
+
+	h := newHandle(key)
+	if h == 0 {
+		t.Fatal("newHandle: unexpected zero handle")
+	}
+	defer free(h)
+	for b := msg; len(b) > 0; b = b[16:] {
+		if s := update(h, b[:16]); s != statusOK {
+			t.Fatalf("update: expected %d, got %d", statusOK, s)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		out := make([]byte, 16)
+		if s := finish(h, out); s != statusOK || !bytes.Equal(out, want) {
+			t.Fatalf("finish #%d: expected (%d, %x), got (%d, %x)",
+				i, statusOK, want, s, out)
+		}
+	}
+	reset(h)
+	update(h, msg)
+	if finish(h, out); !bytes.Equal(out, want) {
+		t.Fatalf("after reset: expected %x, got %x", want, out)
+	}
+}
+
+// TestExportsInvalid tests that invalid arguments are reported
+// instead of panicking.
+func TestExportsInvalid(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	out := make([]byte, 16)
+
+	if s := sum(make([]byte, 16), nil, out); s != statusInvalid {
+		t.Fatalf("sum with zero key: expected %d, got %d", statusInvalid, s)
+	}
+	if s := sum(key, make([]byte, 17), out); s != statusInvalid {
+		t.Fatalf("sum with partial block: expected %d, got %d", statusInvalid, s)
+	}
+	if h := newHandle(make([]byte, 16)); h != 0 {
+		t.Fatalf("newHandle with zero key: expected 0, got %d", h)
+	}
+	if s := update(0, nil); s != statusInvalid {
+		t.Fatalf("update with zero handle: expected %d, got %d", statusInvalid, s)
+	}
+	if s := finish(0, out); s != statusInvalid {
+		t.Fatalf("finish with zero handle: expected %d, got %d", statusInvalid, s)
+	}
+	reset(0)
+	free(0)
+
+	// NULL pointers arrive as nil slices.
+	if s := sum(nil, nil, out); s != statusInvalid {
+		t.Fatalf("sum with nil key: expected %d, got %d", statusInvalid, s)
+	}
+	if s := sum(key, nil, nil); s != statusInvalid {
+		t.Fatalf("sum with nil out: expected %d, got %d", statusInvalid, s)
+	}
+	if h := newHandle(nil); h != 0 {
+		t.Fatalf("newHandle with nil key: expected 0, got %d", h)
+	}
+
+	h := newHandle(key)
+	defer free(h)
+	if s := update(h, make([]byte, 15)); s != statusInvalid {
+		t.Fatalf("update with partial block: expected %d, got %d", statusInvalid, s)
+	}
+	if s := finish(h, nil); s != statusInvalid {
+		t.Fatalf("finish with nil out: expected %d, got %d", statusInvalid, s)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build cgo
+
+// Command cexport exports POLYVAL with a C ABI.
+//
+// Build it as a shared library with
+//
+//	go build -buildmode=c-shared -o libpolyval.so ./cexport
+//
+// and use it through polyval.h in this directory, which is the
+// stable interface. The header generated by the go command is
+// not.
+package main
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// goBytes returns the n bytes at p without copying them.
+//
+// It returns nil if p is NULL.
+func goBytes(p *C.uint8_t, n C.size_t) []byte {
+	if p == nil || n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(p)), n)
+}
+
+//export polyval_sum
+func polyval_sum(key *C.uint8_t, data *C.uint8_t, n C.size_t, out *C.uint8_t) C.int {
+	if data == nil && n != 0 {
+		return statusInvalid
+	}
+	return C.int(sum(goBytes(key, 16), goBytes(data, n), goBytes(out, 16)))
+}
+
+//export polyval_new
+func polyval_new(key *C.uint8_t) C.uintptr_t {
+	return C.uintptr_t(newHandle(goBytes(key, 16)))
+}
+
+//export polyval_update
+func polyval_update(h C.uintptr_t, data *C.uint8_t, n C.size_t) C.int {
+	if data == nil && n != 0 {
+		return statusInvalid
+	}
+	return C.int(update(cgo.Handle(h), goBytes(data, n)))
+}
+
+//export polyval_finish
+func polyval_finish(h C.uintptr_t, out *C.uint8_t) C.int {
+	return C.int(finish(cgo.Handle(h), goBytes(out, 16)))
+}
+
+//export polyval_reset
+func polyval_reset(h C.uintptr_t) {
+	reset(cgo.Handle(h))
+}
+
+//export polyval_free
+func polyval_free(h C.uintptr_t) {
+	free(cgo.Handle(h))
+}
@@ -0,0 +1,57 @@
+/*
+ * POLYVAL per RFC 8452.
+ *
+ * Keys are 16 bytes and cannot be all zero. Inputs must be a
+ * multiple of 16 bytes long; callers must pad them. Digests
+ * are 16 bytes.
+ *
+ * Functions that return int return POLYVAL_OK on success and
+ * POLYVAL_EINVAL if an argument is invalid, including a NULL
+ * key or out pointer, or a NULL data pointer with a non-zero
+ * length. Passing a handle that has already been freed is
+ * undefined.
+ */
+#ifndef POLYVAL_H
+#define POLYVAL_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+#define POLYVAL_OK 0
+#define POLYVAL_EINVAL (-1)
+
+/* polyval_t is a handle to a streaming POLYVAL state. Zero is
+ * never a valid handle. */
+typedef uintptr_t polyval_t;
+
+/* polyval_sum writes the POLYVAL hash of data to out. */
+int polyval_sum(const uint8_t key[16], const uint8_t *data, size_t len,
+                uint8_t out[16]);
+
+/* polyval_new returns a new streaming state, or zero if the key
+ * is invalid. It must be released with polyval_free. */
+polyval_t polyval_new(const uint8_t key[16]);
+
+/* polyval_update writes data to the running hash. */
+int polyval_update(polyval_t p, const uint8_t *data, size_t len);
+
+/* polyval_finish writes the current hash to out. It does not
+ * change the running hash. */
+int polyval_finish(polyval_t p, uint8_t out[16]);
+
+/* polyval_reset sets the hash to its original state. */
+void polyval_reset(polyval_t p);
+
+/* polyval_free zeroes and releases p. p must not be used
+ * afterward. Freeing zero does nothing. */
+void polyval_free(polyval_t p);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif /* POLYVAL_H */
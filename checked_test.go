@@ -0,0 +1,98 @@
+package polyval
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestChecked tests that Checked matches Polyval.
+func TestChecked(t *testing.T) {
+	runTests(t, testChecked)
+}
+
+func testChecked(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	for i := 0; i < 100; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		msg := make([]byte, 16*rng.Intn(100))
+		rng.Read(msg)
+
+		c, err := NewChecked(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Update(msg); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		want, _ := New(key)
+		want.Update(msg)
+		got, err := c.Sum(nil)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if !bytes.Equal(got, want.Sum(nil)) {
+			t.Fatalf("#%d: expected %x, got %x", i, want.Sum(nil), got)
+		}
+	}
+}
+
+// TestCheckedFault tests that Checked detects faults in its
+// state and its powers of the key.
+func TestCheckedFault(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262")
+
+	c, _ := NewChecked(key)
+	c.y.Lo ^= 1
+	if _, err := c.Sum(nil); !errors.Is(err, ErrFault) {
+		t.Fatalf("expected ErrFault, got %v", err)
+	}
+	if err := c.Update(msg); !errors.Is(err, ErrFault) {
+		t.Fatalf("expected ErrFault, got %v", err)
+	}
+	c.Reset()
+	if err := c.Update(msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Sum(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ = NewChecked(key)
+	c.p.pow[15].Hi ^= 1 << 63
+	if err := c.Update(msg); !errors.Is(err, ErrFault) {
+		t.Fatalf("expected ErrFault, got %v", err)
+	}
+	if b, err := c.Sum(nil); !errors.Is(err, ErrFault) || b != nil {
+		t.Fatalf("expected ErrFault, got %x, %v", b, err)
+	}
+}
+
+// TestCheckedWrongProduct tests that Checked detects a wrong
+// product from the fast kernel in the middle of a hash.
+func TestCheckedWrongProduct(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262")
+
+	c, err := NewChecked(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(msg); err != nil {
+		t.Fatal(err)
+	}
+	c.p.y.Hi ^= 1 << 63
+	if err := c.Update(msg); !errors.Is(err, ErrFault) {
+		t.Fatalf("expected ErrFault, got %v", err)
+	}
+	if b, err := c.Sum(nil); !errors.Is(err, ErrFault) || b != nil {
+		t.Fatalf("expected ErrFault, got %x, %v", b, err)
+	}
+}
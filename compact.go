@@ -0,0 +1,114 @@
+package polyval
+
+import (
+	"encoding/binary"
+
+	"github.com/ericlagergren/subtle"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// Compact is an implementation of POLYVAL that does not store
+// a table of powers of the hash key.
+//
+// Its state is 32 bytes instead of 288, which helps programs
+// that keep many hashers alive at once, like one per network
+// connection. In exchange, each call to Update computes the
+// powers of the key it needs, which costs one multiplication
+// per block up to sixteen blocks. Update is much slower than
+// Polyval's for inputs up to a few hundred bytes and within a
+// factor of two for inputs of several kilobytes.
+//
+// Otherwise, it behaves the same as Polyval.
+type Compact struct {
+	// Make Compact non-comparable to prevent accidental
+	// non-constant time comparisons.
+	_ [0]func()
+	// h is the hash key.
+	//
+	// It is an array so that Update can copy it with copy;
+	// see expandPow.
+	h [1]field.Element
+	// y is the running state.
+	y field.Element
+}
+
+// NewCompact creates a Compact.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func NewCompact(key []byte) (*Compact, error) {
+	var p Compact
+	if err := p.Init(key); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Init initializes a Compact.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func (p *Compact) Init(key []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	p.h[0].SetBytes(key)
+	return nil
+}
+
+// Size returns the size of a POLYVAL digest.
+func (p *Compact) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (p *Compact) BlockSize() int {
+	return 16
+}
+
+// Reset sets the hash to its original state.
+func (p *Compact) Reset() {
+	p.y = field.Element{}
+}
+
+// Wipe zeroes the key and the running hash.
+//
+// p must be initialized again before it is used.
+func (p *Compact) Wipe() {
+	*p = Compact{}
+}
+
+// Update writes one or more blocks to the running hash.
+//
+// If len(block) is not divisible by BlockSize, Update will panic.
+func (p *Compact) Update(blocks []byte) {
+	if len(blocks)%16 != 0 {
+		panic("polyval: invalid input length")
+	}
+	if len(blocks) == 0 {
+		return
+	}
+
+	// Compute the powers of h that MulBlocks needs for this
+	// call on the stack. For short inputs this is no more work
+	// than multiplying by h once per block, and long inputs
+	// can use the wide kernels.
+	n := len(blocks) / 16
+	if n > 16 {
+		n = 16
+	}
+	var pow [16]field.Element
+	copy(pow[15:], p.h[:])
+	expandPow(&pow, 16-n, 15)
+	field.MulBlocks(&p.y, &pow, blocks)
+}
+
+// Sum appends the current hash to b and returns the resulting
+// slice.
+//
+// It does not change the underlying hash state.
+func (p *Compact) Sum(b []byte) []byte {
+	ret, out := subtle.SliceForAppend(b, 16)
+	binary.LittleEndian.PutUint64(out[0:8], p.y.Lo)
+	binary.LittleEndian.PutUint64(out[8:16], p.y.Hi)
+	return ret
+}
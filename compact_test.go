@@ -0,0 +1,74 @@
+package polyval
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+	"unsafe"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestCompact tests that Compact matches Polyval.
+func TestCompact(t *testing.T) {
+	runTests(t, testCompact)
+}
+
+func testCompact(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	p, _ := New(key)
+	c, _ := NewCompact(key)
+
+	blocks := make([]byte, 16*40)
+	for i := 0; i < 1000; i++ {
+		b := blocks[:16*rng.Intn(40)]
+		rng.Read(b)
+		p.Update(b)
+		c.Update(b)
+		if want, got := p.Sum(nil), c.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+		if rng.Intn(10) == 0 {
+			p.Reset()
+			c.Reset()
+		}
+	}
+}
+
+// TestCompactSize tests that Compact stays small.
+func TestCompactSize(t *testing.T) {
+	if n := unsafe.Sizeof(Compact{}); n != 32 {
+		t.Fatalf("expected 32 bytes, got %d", n)
+	}
+}
+
+// TestCompactZeroKey tests that NewCompact rejects invalid
+// keys.
+func TestCompactZeroKey(t *testing.T) {
+	for _, key := range [][]byte{nil, make([]byte, 15), make([]byte, 16)} {
+		if _, err := NewCompact(key); err == nil {
+			t.Fatalf("%x: expected an error", key)
+		}
+	}
+}
+
+func BenchmarkCompact(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			b.SetBytes(int64(n) * 16)
+			p, _ := NewCompact(unhex("01000000000000000000000000000000"))
+			x := make([]byte, n*p.BlockSize())
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				p.Update(x)
+			}
+			byteSink = p.Sum(nil)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package polyval
+
+import (
+	"flag"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+var dudect = flag.Bool("dudect", false, "run the timing leakage tests (slow)")
+
+// TestDudect checks Init, Update, and Sum for data-dependent
+// timing with the method from "dude, is my code constant time?"
+// by Reparaz, Balasch, and Verbauwhede.
+//
+// It times the functions with a fixed key and input and with
+// random keys and inputs, interleaved at random, and compares
+// the two distributions with Welch's t-test. It only runs with
+// -dudect, since it takes a while and can be disturbed by other
+// load on the machine:
+//
+//    go test -run Dudect -dudect -v
+//
+// See https://eprint.iacr.org/2016/1123.pdf
+func TestDudect(t *testing.T) {
+	if !*dudect {
+		t.Skip("skipping without -dudect")
+	}
+	runTests(t, testDudect)
+}
+
+func testDudect(t *testing.T) {
+	const (
+		// samples is the number of measurements.
+		samples = 1 << 20
+		// batch is the number of calls per measurement, so
+		// that each takes much longer than reading the clock.
+		batch = 16
+		// nblocks is the number of blocks per call, which
+		// covers the wide and the single-block code paths.
+		nblocks = 19
+		// threshold is the t statistic above which the
+		// distributions are considered different. dudect
+		// uses 10 for "definitely not constant time."
+		threshold = 10
+	)
+
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+
+	// The fixed class uses a key and input with few bits set,
+	// which is where data-dependent shortcuts are most likely.
+	fixedKey := make([]byte, 16)
+	fixedKey[0] = 1
+	fixedData := make([]byte, 16*nblocks)
+
+	const ninputs = 256
+	keys := make([][]byte, ninputs)
+	data := make([][]byte, ninputs)
+	for i := range keys {
+		keys[i] = make([]byte, 16)
+		rng.Read(keys[i])
+		keys[i][0] |= 1
+		data[i] = make([]byte, 16*nblocks)
+		rng.Read(data[i])
+	}
+
+	var p Polyval
+	var sum [Size]byte
+	// Both classes are copied into the same buffers so that
+	// they do not differ in where they are in memory or how
+	// recently they were used.
+	key := make([]byte, 16)
+	msg := make([]byte, 16*nblocks)
+	classes := make([]bool, samples)
+	times := make([]float64, samples)
+	for i := range times {
+		random := rng.Intn(2) == 1
+		if random {
+			j := rng.Intn(ninputs)
+			copy(key, keys[j])
+			copy(msg, data[j])
+		} else {
+			copy(key, fixedKey)
+			copy(msg, fixedData)
+		}
+		start := time.Now()
+		for j := 0; j < batch; j++ {
+			p.Init(key)
+			p.Update(msg)
+			p.Sum(sum[:0])
+		}
+		times[i] = float64(time.Since(start))
+		classes[i] = random
+	}
+
+	// Like dudect, also test measurements below several
+	// percentiles, since outliers from interrupts and
+	// scheduling inflate the variance and hide small leaks.
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+	for _, pct := range []float64{1, 0.99, 0.9, 0.5} {
+		cutoff := sorted[int(pct*float64(len(sorted)-1))]
+		var a, b welford
+		for i, x := range times {
+			if x > cutoff {
+				continue
+			}
+			if classes[i] {
+				b.add(x)
+			} else {
+				a.add(x)
+			}
+		}
+		tt := welch(&a, &b)
+		t.Logf("percentile %.2f: t = %.2f (fixed: %.0fns, random: %.0fns)",
+			pct, tt, a.mean/batch, b.mean/batch)
+		if math.Abs(tt) > threshold {
+			t.Fatalf("percentile %.2f: |t| = %.2f > %d", pct, math.Abs(tt), threshold)
+		}
+	}
+}
+
+// welford computes a running mean and variance.
+type welford struct {
+	n, mean, m2 float64
+}
+
+func (w *welford) add(x float64) {
+	w.n++
+	d := x - w.mean
+	w.mean += d / w.n
+	w.m2 += d * (x - w.mean)
+}
+
+func (w *welford) variance() float64 {
+	return w.m2 / (w.n - 1)
+}
+
+// welch returns Welch's t statistic for a and b.
+func welch(a, b *welford) float64 {
+	return (a.mean - b.mean) / math.Sqrt(a.variance()/a.n+b.variance()/b.n)
+}
@@ -0,0 +1,111 @@
+package polyval
+
+import (
+	"crypto/rand"
+	"sync"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// FastHash is a fast, non-cryptographic 64-bit hash function
+// built on POLYVAL.
+//
+// FastHash is NOT a MAC or a cryptographic hash function. It is
+// intended for hash tables, sharding, and deduplication where
+// the seed is secret and the outputs are not revealed to an
+// attacker. Outputs leak information about the seed, so an
+// attacker who can observe them can find collisions.
+//
+// Unlike Polyval, FastHash accepts input of any length. The
+// input is zero-padded to a multiple of the block size and
+// followed by a block containing its length and a constant
+// non-zero domain bit.
+//
+// A FastHash is safe for concurrent use.
+type FastHash struct {
+	p Polyval
+}
+
+// NewFastHash creates a FastHash from a 16-byte seed.
+//
+// The seed cannot be all zero. Hashes computed with the same
+// seed are stable across processes and machines.
+func NewFastHash(seed []byte) (*FastHash, error) {
+	var f FastHash
+	if err := f.p.Init(seed); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Sum64 returns the 64-bit hash of data.
+func (f *FastHash) Sum64(data []byte) uint64 {
+	pow := &f.p.pow
+	n := len(data)
+
+	// Length block. The domain bit keeps it non-zero, otherwise
+	// the hash of the empty input would be zero for every seed.
+	l := field.Element{Lo: uint64(n), Hi: lengthDomain}
+
+	if n <= 16 {
+		// Short inputs are the common case for hash tables.
+		// Skip the block loop and compute
+		//
+		//    POLYVAL(H, M, L) = M*H^2 + L*H
+		//
+		// with two independent multiplications.
+		var buf [16]byte
+		copy(buf[:], data)
+		var m field.Element
+		m.SetBytes(buf[:])
+		field.Mul(&m, &pow[len(pow)-2])
+		field.Mul(&l, &pow[len(pow)-1])
+		return m.Lo ^ l.Lo
+	}
+
+	var y field.Element
+	full := n &^ 15
+	field.MulBlocks(&y, pow, data[:full])
+	if full < n {
+		var buf [16]byte
+		copy(buf[:], data[full:])
+		field.MulBlocks(&y, pow, buf[:])
+	}
+	y.Lo ^= l.Lo
+	y.Hi ^= l.Hi
+	field.Mul(&y, &pow[len(pow)-1])
+	return y.Lo
+}
+
+// lengthDomain is set in the high half of the length block.
+const lengthDomain = 1 << 63
+
+var (
+	fastHashOnce sync.Once
+	fastHash     FastHash
+)
+
+// Hash64 returns the 64-bit FastHash of data using a random
+// per-process seed.
+//
+// The seed is generated from crypto/rand the first time Hash64
+// is called, so results are NOT stable across processes. Use
+// NewFastHash for stable hashes.
+//
+// See FastHash for important security caveats.
+func Hash64(data []byte) uint64 {
+	fastHashOnce.Do(func() {
+		seed := make([]byte, 16)
+		for {
+			if _, err := rand.Read(seed); err != nil {
+				panic(err)
+			}
+			// The zero seed is invalid, but only occurs with
+			// probability 2^-128.
+			if err := fastHash.p.Init(seed); err == nil {
+				break
+			}
+		}
+	})
+	return fastHash.Sum64(data)
+}
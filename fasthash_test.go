@@ -0,0 +1,154 @@
+package polyval
+
+import (
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// sum64Ref is the reference implementation of FastHash.Sum64.
+func sum64Ref(key, data []byte) uint64 {
+	p, err := New(key)
+	if err != nil {
+		panic(err)
+	}
+	buf := make([]byte, (len(data)+15)&^15+16)
+	copy(buf, data)
+	binary.LittleEndian.PutUint64(buf[len(buf)-16:], uint64(len(data)))
+	binary.LittleEndian.PutUint64(buf[len(buf)-8:], lengthDomain)
+	p.Update(buf)
+	return binary.LittleEndian.Uint64(p.Sum(nil))
+}
+
+// TestFastHash tests FastHash against a reference
+// implementation built on Polyval.
+func TestFastHash(t *testing.T) {
+	runTests(t, testFastHash)
+}
+
+func testFastHash(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+
+	key := make([]byte, 16)
+	data := make([]byte, 300)
+	for i := 0; i < 100; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		f, err := NewFastHash(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rng.Read(data)
+		for n := 0; n <= len(data); n++ {
+			want := sum64Ref(key, data[:n])
+			if got := f.Sum64(data[:n]); got != want {
+				t.Fatalf("#%d: %d: expected %#x, got %#x", i, n, want, got)
+			}
+		}
+	}
+}
+
+// TestFastHashLength tests that zero padding does not cause
+// trivial collisions.
+func TestFastHashLength(t *testing.T) {
+	f, err := NewFastHash(unhex("25629347589242761d31f826ba4b757b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[uint64]int)
+	buf := make([]byte, 64)
+	for n := 0; n <= len(buf); n++ {
+		h := f.Sum64(buf[:n])
+		if m, ok := seen[h]; ok {
+			t.Fatalf("%d zero bytes collides with %d zero bytes", n, m)
+		}
+		seen[h] = n
+	}
+}
+
+// TestFastHashEmpty tests that the hash of the empty input is
+// non-zero and depends on the seed.
+func TestFastHashEmpty(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+
+	seen := make(map[uint64]int)
+	key := make([]byte, 16)
+	for i := 0; i < 100; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		f, err := NewFastHash(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := f.Sum64(nil)
+		if h == 0 {
+			t.Fatalf("seed #%d (%x): empty input hashes to zero", i, key)
+		}
+		if j, ok := seen[h]; ok {
+			t.Fatalf("seed #%d: empty input collides with seed #%d", i, j)
+		}
+		seen[h] = i
+	}
+}
+
+// TestHash64 tests that Hash64 is deterministic within
+// a process.
+func TestHash64(t *testing.T) {
+	data := []byte("hello, world")
+	if Hash64(data) != Hash64(data) {
+		t.Fatal("Hash64 is not deterministic")
+	}
+	if Hash64(data) == Hash64(data[:len(data)-1]) {
+		t.Fatal("unexpected collision")
+	}
+}
+
+var u64Sink uint64
+
+func BenchmarkHash64(b *testing.B) {
+	for _, n := range []int{8, 16, 64, 1024} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			data := make([]byte, n)
+			b.SetBytes(int64(n))
+			for i := 0; i < b.N; i++ {
+				u64Sink = Hash64(data)
+			}
+		})
+	}
+}
+
+// TestFastHashConcurrent tests that a FastHash can be used from
+// multiple goroutines.
+func TestFastHashConcurrent(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	f, err := NewFastHash(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(uint64(i)))
+			data := make([]byte, 300)
+			for j := 0; j < 200; j++ {
+				data := data[:rng.Intn(len(data))]
+				rng.Read(data)
+				want := sum64Ref(key, data)
+				if got := f.Sum64(data); got != want {
+					t.Errorf("expected %#x, got %#x", want, got)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,177 @@
+//go:build go1.18
+
+package polyval
+
+import (
+	"bytes"
+	"testing"
+
+	tink "github.com/google/tink/go/aead/subtle"
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// addSeeds adds the RFC 8452 vector and random inputs around
+// the kernels' strides to the seed corpus.
+func addSeeds(f *testing.F, extra ...interface{}) {
+	f.Add(append([]interface{}{
+		unhex("25629347589242761d31f826ba4b757b"),
+		unhex("4f4f95668c83dfb6401762bb2d01a262" +
+			"d1a24ddd2721d006bbe45f20d3c9f362"),
+	}, extra...)...)
+
+	rng := rand.New(rand.NewSource(8452))
+	for _, n := range []int{0, 1, 4, 7, 8, 9, 15, 16, 17, 31, 32, 33, 64, 65} {
+		key := make([]byte, 16)
+		rng.Read(key)
+		data := make([]byte, 16*n)
+		rng.Read(data)
+		f.Add(append([]interface{}{key, data}, extra...)...)
+	}
+}
+
+// sumGeneric returns the POLYVAL hash of data computed only with
+// the generic implementation.
+func sumGeneric(key, data []byte) []byte {
+	var pow [16]field.Element
+	pow[len(pow)-1].SetBytes(key)
+	for i := len(pow) - 2; i >= 0; i-- {
+		pow[i] = pow[i+1]
+		field.MulGeneric(&pow[i], &pow[len(pow)-1])
+	}
+	var y field.Element
+	field.MulBlocksGeneric(&y, &pow, data)
+	return marshal(y)
+}
+
+// FuzzSum tests that Sum matches the generic implementation,
+// Google Tink's POLYVAL, and GHASH.
+func FuzzSum(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, key, data []byte) {
+		data = data[:len(data)&^15]
+
+		p, err := New(key)
+		if len(key) != 16 || bytes.Equal(key, make([]byte, 16)) {
+			if err == nil {
+				t.Fatalf("%x: expected an error", key)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Update(data)
+		got := p.Sum(nil)
+
+		if want := sumGeneric(key, data); !bytes.Equal(got, want) {
+			t.Fatalf("generic: expected %x, got %x", want, got)
+		}
+		if want := Sum(key, data); !bytes.Equal(got, want[:]) {
+			t.Fatalf("Sum: expected %x, got %x", want, got)
+		}
+
+		tk, err := tink.NewPolyval(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tk.Update(data)
+		if want := tk.Finish(); !bytes.Equal(got, want[:]) {
+			t.Fatalf("tink: expected %x, got %x", want, got)
+		}
+
+		gcmToPolyval(t, key, data)
+		polyvalToGCM(t, key, data)
+	})
+}
+
+// FuzzUpdate tests that splitting the input across calls to
+// Update does not change the hash.
+//
+// Each byte of cuts is the number of blocks in the next call to
+// Update, modulo 33.
+func FuzzUpdate(f *testing.F) {
+	addSeeds(f, []byte{1, 7, 8, 16, 17, 0, 32})
+	f.Fuzz(func(t *testing.T, key, data, cuts []byte) {
+		data = data[:len(data)&^15]
+		if _, err := New(key); err != nil {
+			return
+		}
+		want := sumGeneric(key, data)
+
+		p, _ := New(key)
+		rest := data
+		for _, c := range cuts {
+			n := 16 * (int(c) % 33)
+			if n > len(rest) {
+				n = len(rest)
+			}
+			p.Update(rest[:n])
+			rest = rest[n:]
+		}
+		p.Update(rest)
+		if got := p.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("expected %x, got %x", want, got)
+		}
+	})
+}
+
+// FuzzUnmarshalBinary tests that UnmarshalBinary either rejects
+// its input or restores a state that behaves the same as a new
+// Polyval with the same key and running hash.
+func FuzzUnmarshalBinary(f *testing.F) {
+	rng := rand.New(rand.NewSource(8452))
+	for i := 0; i < 8; i++ {
+		key := make([]byte, 16)
+		rng.Read(key)
+		p, err := New(key)
+		if err != nil {
+			continue
+		}
+		blocks := make([]byte, 16*i)
+		rng.Read(blocks)
+		p.Update(blocks)
+		data, _ := p.MarshalBinary()
+		f.Add(data, blocks)
+
+		// A corrupt power of the key.
+		bad := append([]byte(nil), data...)
+		bad[len(bad)-1-i] ^= 1
+		f.Add(bad, blocks)
+	}
+	f.Add(make([]byte, marshaledSize), []byte(nil))
+	f.Add([]byte(nil), []byte(nil))
+
+	f.Fuzz(func(t *testing.T, data, blocks []byte) {
+		blocks = blocks[:len(blocks)&^15]
+
+		p, _ := New(unhex("25629347589242761d31f826ba4b757b"))
+		p.Update(blocks)
+		old := *p
+		if err := p.UnmarshalBinary(data); err != nil {
+			if !StatesEqual(p, &old) || p.pow != old.pow {
+				t.Fatalf("UnmarshalBinary changed p after failing: %v", err)
+			}
+			return
+		}
+		if got, _ := p.MarshalBinary(); !bytes.Equal(got, data) {
+			t.Fatalf("expected %x, got %x", data, got)
+		}
+
+		want, err := New(data[:16])
+		if err != nil {
+			t.Fatalf("UnmarshalBinary accepted an invalid key: %v", err)
+		}
+		want.y.SetBytes(data[16:32])
+		if !StatesEqual(p, want) || p.pow != want.pow {
+			t.Fatal("restored state differs from a new Polyval")
+		}
+
+		p.Update(blocks)
+		want.Update(blocks)
+		if got := p.Sum(nil); !bytes.Equal(got, want.Sum(nil)) {
+			t.Fatalf("expected %x, got %x", want.Sum(nil), got)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package gf128
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// The following tests check the field axioms over random
+// elements. Unlike fixed test vectors, they catch structural
+// mistakes in the reduction.
+
+const axiomIters = 10000
+
+// TestAddAxioms tests that addition is associative and
+// commutative, that zero is the additive identity, and that
+// every element is its own additive inverse.
+func TestAddAxioms(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < axiomIters; i++ {
+		a, b, c := randElem(rng), randElem(rng), randElem(rng)
+
+		var lhs, rhs Element
+		lhs.Add(a, b)
+		lhs.Add(lhs, c)
+		rhs.Add(b, c)
+		rhs.Add(a, rhs)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("(%v+%v)+%v != %v+(%v+%v)", a, b, c, a, b, c)
+		}
+
+		lhs.Add(a, b)
+		rhs.Add(b, a)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("%v+%v != %v+%v", a, b, b, a)
+		}
+
+		if lhs.Add(a, Element{}).Equal(a) != 1 {
+			t.Fatalf("%v+0 != %v", a, a)
+		}
+		if lhs.Add(a, a).IsZero() != 1 {
+			t.Fatalf("%v+%v != 0", a, a)
+		}
+	}
+}
+
+// TestMulAxioms tests that multiplication is associative and
+// commutative, that One is the multiplicative identity, and
+// that zero annihilates.
+func TestMulAxioms(t *testing.T) {
+	runTests(t, testMulAxioms)
+}
+
+func testMulAxioms(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < axiomIters; i++ {
+		a, b, c := randElem(rng), randElem(rng), randElem(rng)
+
+		var lhs, rhs Element
+		lhs.Mul(a, b)
+		lhs.Mul(lhs, c)
+		rhs.Mul(b, c)
+		rhs.Mul(a, rhs)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("(%v*%v)*%v != %v*(%v*%v)", a, b, c, a, b, c)
+		}
+
+		lhs.Mul(a, b)
+		rhs.Mul(b, a)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("%v*%v != %v*%v", a, b, b, a)
+		}
+
+		if lhs.Mul(a, One()).Equal(a) != 1 {
+			t.Fatalf("%v*1 != %v", a, a)
+		}
+		if lhs.Mul(a, Element{}).IsZero() != 1 {
+			t.Fatalf("%v*0 != 0", a)
+		}
+	}
+}
+
+// TestDistributive tests that multiplication distributes over
+// addition.
+func TestDistributive(t *testing.T) {
+	runTests(t, testDistributive)
+}
+
+func testDistributive(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < axiomIters; i++ {
+		a, b, c := randElem(rng), randElem(rng), randElem(rng)
+
+		// a*(b+c)
+		var lhs Element
+		lhs.Add(b, c)
+		lhs.Mul(a, lhs)
+
+		// a*b + a*c
+		var ab, ac, rhs Element
+		ab.Mul(a, b)
+		ac.Mul(a, c)
+		rhs.Add(ab, ac)
+
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("%v*(%v+%v) != %v*%v+%v*%v", a, b, c, a, b, a, c)
+		}
+	}
+}
+
+// TestMulInverse tests that x*x^-1 = 1 and (x*y)^-1 =
+// x^-1*y^-1 for non-zero x and y.
+func TestMulInverse(t *testing.T) {
+	runTests(t, testMulInverse)
+}
+
+func testMulInverse(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < axiomIters/10; i++ {
+		x, y := randElem(rng), randElem(rng)
+		if x.IsZero() == 1 || y.IsZero() == 1 {
+			continue
+		}
+
+		var xinv, yinv Element
+		xinv.Inv(x)
+		yinv.Inv(y)
+
+		var z Element
+		if z.Mul(x, xinv).Equal(One()) != 1 {
+			t.Fatalf("%v*%v^-1 != 1", x, x)
+		}
+
+		var lhs, rhs Element
+		lhs.Mul(x, y)
+		lhs.Inv(lhs)
+		rhs.Mul(xinv, yinv)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("(%v*%v)^-1 != %v^-1*%v^-1", x, y, x, y)
+		}
+
+		if z.Inv(xinv).Equal(x) != 1 {
+			t.Fatalf("(%v^-1)^-1 != %v", x, x)
+		}
+	}
+}
+
+// TestFrobenius tests that (a+b)^2 = a^2 + b^2, which holds
+// because the field has characteristic 2.
+func TestFrobenius(t *testing.T) {
+	runTests(t, testFrobenius)
+}
+
+func testFrobenius(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < axiomIters; i++ {
+		a, b := randElem(rng), randElem(rng)
+
+		var lhs Element
+		lhs.Add(a, b)
+		lhs.Square(lhs)
+
+		var a2, b2, rhs Element
+		a2.Square(a)
+		b2.Square(b)
+		rhs.Add(a2, b2)
+
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("(%v+%v)^2 != %v^2+%v^2", a, b, a, b)
+		}
+	}
+}
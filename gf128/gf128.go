@@ -0,0 +1,309 @@
+// Package gf128 implements arithmetic in POLYVAL's field.
+//
+// The field is GF(2^128) defined by the irreducible polynomial
+//
+//    x^128 + x^127 + x^126 + x^121 + 1.
+//
+// Elements use the same little-endian encoding as POLYVAL and
+// multiplication is POLYVAL's dot operation
+//
+//    dot(a, b) = a * b * x^-128,
+//
+// which is ordinary field multiplication over the Montgomery
+// representation of each element. Arithmetic is consistent
+// within this package, but note that the multiplicative
+// identity is One, not the element encoded as 0x01 followed by
+// fifteen zero bytes.
+//
+// For more information, see [rfc8452].
+//
+// [rfc8452]: https://datatracker.ietf.org/doc/html/rfc8452#section-3
+package gf128
+
+import (
+	"crypto/rand"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+const (
+	// Size is the size in bytes of an encoded Element.
+	Size = 16
+)
+
+// Element is an element in GF(2^128).
+//
+// The zero value is the additive identity.
+type Element struct {
+	// Make Element non-comparable to prevent accidental
+	// non-constant time comparisons.
+	_ [0]func()
+	// lo and hi are the low and high 64 bits of the
+	// little-endian element.
+	lo, hi uint64
+}
+
+var (
+	_ encoding.TextMarshaler   = Element{}
+	_ encoding.TextUnmarshaler = (*Element)(nil)
+)
+
+// One returns the multiplicative identity.
+func One() Element {
+	// x^128 mod x^128 + x^127 + x^126 + x^121 + 1
+	return Element{lo: 1, hi: 0xc200000000000000}
+}
+
+// NewElement returns the element encoded by the 16-byte
+// little-endian string b.
+func NewElement(b []byte) (Element, error) {
+	var z Element
+	if _, err := z.SetBytes(b); err != nil {
+		return Element{}, err
+	}
+	return z, nil
+}
+
+// Rand returns a uniformly random element read from r.
+//
+// If r is nil, Rand uses crypto/rand.Reader.
+func Rand(r io.Reader) (Element, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var b [Size]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return Element{}, err
+	}
+	var z Element
+	z.SetBytes(b[:])
+	return z, nil
+}
+
+// RandNonZero is like Rand, but never returns zero.
+func RandNonZero(r io.Reader) (Element, error) {
+	// The probability of reading zero is 2^-128, so in practice
+	// this only loops if r is broken.
+	for i := 0; i < 100; i++ {
+		z, err := Rand(r)
+		if err != nil {
+			return Element{}, err
+		}
+		if z.IsZero() == 0 {
+			return z, nil
+		}
+	}
+	return Element{}, errors.New("gf128: unable to generate a non-zero element")
+}
+
+// SetBytes sets z to the 16-byte little-endian element b and
+// returns z.
+func (z *Element) SetBytes(b []byte) (*Element, error) {
+	if len(b) != Size {
+		return nil, fmt.Errorf("gf128: invalid element size: %d", len(b))
+	}
+	z.lo = binary.LittleEndian.Uint64(b[0:8])
+	z.hi = binary.LittleEndian.Uint64(b[8:16])
+	return z, nil
+}
+
+// Bytes returns the 16-byte little-endian encoding of z.
+func (z Element) Bytes() []byte {
+	b := make([]byte, Size)
+	binary.LittleEndian.PutUint64(b[0:8], z.lo)
+	binary.LittleEndian.PutUint64(b[8:16], z.hi)
+	return b
+}
+
+// ParseElement returns the element encoded by s, which is the
+// hexadecimal encoding of the 16-byte little-endian element.
+//
+// ParseElement is the inverse of MarshalText.
+func ParseElement(s string) (Element, error) {
+	var z Element
+	if err := z.UnmarshalText([]byte(s)); err != nil {
+		return Element{}, err
+	}
+	return z, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// The result is the hexadecimal encoding of Bytes. It does not
+// return an error.
+func (z Element) MarshalText() ([]byte, error) {
+	b := z.Bytes()
+	out := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(out, b)
+	return out, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// text must be the hexadecimal encoding of a 16-byte
+// little-endian element.
+func (z *Element) UnmarshalText(text []byte) error {
+	if len(text) != hex.EncodedLen(Size) {
+		return fmt.Errorf("gf128: invalid text size: %d", len(text))
+	}
+	var b [Size]byte
+	if _, err := hex.Decode(b[:], text); err != nil {
+		return fmt.Errorf("gf128: invalid element: %w", err)
+	}
+	z.SetBytes(b[:])
+	return nil
+}
+
+// String returns the hexadecimal representation of z as
+// a 128-bit integer.
+//
+// Unlike MarshalText, the most significant byte is first.
+func (z Element) String() string {
+	return fmt.Sprintf("%#0.16x%0.16x", z.hi, z.lo)
+}
+
+// Equal returns 1 if z == x and 0 otherwise.
+//
+// Equal runs in constant time.
+func (z Element) Equal(x Element) int {
+	v := (z.lo ^ x.lo) | (z.hi ^ x.hi)
+	return int((v|-v)>>63 ^ 1)
+}
+
+// IsZero returns 1 if z == 0 and 0 otherwise.
+//
+// IsZero runs in constant time.
+func (z Element) IsZero() int {
+	return z.Equal(Element{})
+}
+
+// Add sets z = x + y and returns z.
+//
+// Addition is also subtraction since the field has
+// characteristic 2.
+func (z *Element) Add(x, y Element) *Element {
+	z.lo = x.lo ^ y.lo
+	z.hi = x.hi ^ y.hi
+	return z
+}
+
+// Mul sets z = x * y and returns z.
+func (z *Element) Mul(x, y Element) *Element {
+	mul(z, &x, &y)
+	return z
+}
+
+// Square sets z = x^2 and returns z.
+func (z *Element) Square(x Element) *Element {
+	square(z, &x)
+	return z
+}
+
+// Sqrt sets z to the square root of x and returns z.
+//
+// Every element in GF(2^128) has exactly one square root.
+func (z *Element) Sqrt(x Element) *Element {
+	sqrt(z, &x)
+	return z
+}
+
+// Inv sets z = 1/x and returns z.
+//
+// If x == 0, Inv sets z = 0.
+func (z *Element) Inv(x Element) *Element {
+	// Fermat's little theorem: x^(2^128-2) = 1/x.
+	//
+	// Compute t = x^(2^127-1) then square it.
+	t := x
+	for i := 1; i < 127; i++ {
+		square(&t, &t)
+		mul(&t, &t, &x)
+	}
+	square(z, &t)
+	return z
+}
+
+// Select sets z = a if cond == 1 and z = b if cond == 0 and
+// returns z.
+//
+// The behavior is undefined if cond is not 0 or 1.
+//
+// Select runs in constant time.
+func (z *Element) Select(cond int, a, b Element) *Element {
+	m := -uint64(cond)
+	z.lo = b.lo ^ (m & (a.lo ^ b.lo))
+	z.hi = b.hi ^ (m & (a.hi ^ b.hi))
+	return z
+}
+
+// CSwap swaps z and x if cond == 1 and leaves them unchanged
+// if cond == 0.
+//
+// The behavior is undefined if cond is not 0 or 1.
+//
+// CSwap runs in constant time.
+func (z *Element) CSwap(cond int, x *Element) {
+	m := -uint64(cond)
+	t := m & (z.lo ^ x.lo)
+	z.lo ^= t
+	x.lo ^= t
+	t = m & (z.hi ^ x.hi)
+	z.hi ^= t
+	x.hi ^= t
+}
+
+// mul sets z = x*y*x^-128.
+func mul(z, x, y *Element) {
+	acc := field.Element{Lo: x.lo, Hi: x.hi}
+	key := field.Element{Lo: y.lo, Hi: y.hi}
+	field.Mul(&acc, &key)
+	z.lo, z.hi = acc.Lo, acc.Hi
+}
+
+// square sets z = x*x*x^-128.
+func square(z, x *Element) {
+	acc := field.Element{Lo: x.lo, Hi: x.hi}
+	field.Square(&acc)
+	z.lo, z.hi = acc.Lo, acc.Hi
+}
+
+// sqrtx is x^192 * x^(1/2) mod the field polynomial.
+//
+// It is sqrt(x)*x^64 in the Montgomery domain.
+var sqrtx = Element{lo: 0xc474ddbcee2c890f, hi: 0x7a92492492492490}
+
+// sqrt sets z to the square root of x.
+func sqrt(z, x *Element) {
+	// Split x into its even and odd bits
+	//
+	//    x = e(x)^2 + x*o(x)^2
+	//
+	// so that
+	//
+	//    sqrt(x) = e(x) + sqrt(x)*o(x).
+	//
+	// Elements are in the Montgomery domain, so the result is
+	// sqrt(x)*x^64.
+	e := compress(x.lo) | compress(x.hi)<<32
+	o := Element{lo: compress(x.lo>>1) | compress(x.hi>>1)<<32}
+	mul(z, &o, &sqrtx)
+	z.hi ^= e
+}
+
+// compress returns the 32-bit value made of the even bits of
+// x.
+func compress(x uint64) uint64 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x>>4) & 0x00ff00ff00ff00ff
+	x = (x | x>>8) & 0x0000ffff0000ffff
+	x = (x | x>>16) & 0x00000000ffffffff
+	return x
+}
@@ -0,0 +1,41 @@
+//go:build amd64 && gc && !purego && !noasm && !tinygo
+
+package gf128
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+func disableAsm(t *testing.T) {
+	old := field.HaveAsm
+	t.Cleanup(func() {
+		field.HaveAsm = old
+	})
+	field.HaveAsm = false
+}
+
+func disableAVX(t *testing.T) {
+	old := field.HaveAVX
+	t.Cleanup(func() {
+		field.HaveAVX = old
+	})
+	field.HaveAVX = false
+}
+
+func runTests(t *testing.T, fn func(t *testing.T)) {
+	if field.HaveAsm {
+		t.Run("assembly", fn)
+		if field.HaveAVX {
+			t.Run("assemblyNoAVX", func(t *testing.T) {
+				disableAVX(t)
+				fn(t)
+			})
+		}
+	}
+	t.Run("generic", func(t *testing.T) {
+		disableAsm(t)
+		fn(t)
+	})
+}
@@ -0,0 +1,27 @@
+//go:build arm && gc && !purego && !noasm && !tinygo
+
+package gf128
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+func disableAsm(t *testing.T) {
+	old := field.HaveAsm
+	t.Cleanup(func() {
+		field.HaveAsm = old
+	})
+	field.HaveAsm = false
+}
+
+func runTests(t *testing.T, fn func(t *testing.T)) {
+	if field.HaveAsm {
+		t.Run("assembly", fn)
+	}
+	t.Run("generic", func(t *testing.T) {
+		disableAsm(t)
+		fn(t)
+	})
+}
@@ -0,0 +1,9 @@
+//go:build !(amd64 || arm || arm64 || riscv64 || s390x) || !gc || purego || noasm || tinygo
+
+package gf128
+
+import "testing"
+
+func runTests(t *testing.T, fn func(t *testing.T)) {
+	t.Run("generic", fn)
+}
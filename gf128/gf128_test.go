@@ -0,0 +1,285 @@
+package gf128
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval"
+)
+
+var elemSink Element
+
+func unhex(s string) []byte {
+	p, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func randElem(rng *rand.Rand) Element {
+	return Element{lo: rng.Uint64(), hi: rng.Uint64()}
+}
+
+// TestMulPolyval tests that Mul matches a single-block POLYVAL
+// computation, which is dot(X_1, H).
+func TestMulPolyval(t *testing.T) {
+	runTests(t, testMulPolyval)
+}
+
+func testMulPolyval(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		x, h := randElem(rng), randElem(rng)
+		if h.IsZero() == 1 {
+			continue
+		}
+		want := polyval.Sum(h.Bytes(), x.Bytes())
+		var z Element
+		z.Mul(x, h)
+		if got := z.Bytes(); !bytes.Equal(got, want[:]) {
+			t.Fatalf("%v*%v: expected %x, got %x", x, h, want, got)
+		}
+	}
+}
+
+// TestOne tests that One is the multiplicative identity.
+func TestOne(t *testing.T) {
+	runTests(t, testOne)
+}
+
+func testOne(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		x := randElem(rng)
+		var z Element
+		z.Mul(x, One())
+		if z.Equal(x) != 1 {
+			t.Fatalf("%v*1: expected %v, got %v", x, x, z)
+		}
+	}
+}
+
+// TestSelect tests Element.Select.
+func TestSelect(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		a, b := randElem(rng), randElem(rng)
+		var z Element
+		if z.Select(1, a, b).Equal(a) != 1 {
+			t.Fatalf("Select(1, %v, %v): got %v", a, b, z)
+		}
+		if z.Select(0, a, b).Equal(b) != 1 {
+			t.Fatalf("Select(0, %v, %v): got %v", a, b, z)
+		}
+	}
+}
+
+// TestCSwap tests Element.CSwap.
+func TestCSwap(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		a, b := randElem(rng), randElem(rng)
+
+		x, y := a, b
+		x.CSwap(0, &y)
+		if x.Equal(a) != 1 || y.Equal(b) != 1 {
+			t.Fatalf("CSwap(0): expected (%v, %v), got (%v, %v)", a, b, x, y)
+		}
+		x.CSwap(1, &y)
+		if x.Equal(b) != 1 || y.Equal(a) != 1 {
+			t.Fatalf("CSwap(1): expected (%v, %v), got (%v, %v)", b, a, x, y)
+		}
+	}
+}
+
+// TestEqual tests Element.Equal.
+func TestEqual(t *testing.T) {
+	for i, tc := range []struct {
+		x, y Element
+		want int
+	}{
+		{Element{}, Element{}, 1},
+		{One(), One(), 1},
+		{Element{lo: 1}, Element{}, 0},
+		{Element{hi: 1 << 63}, Element{}, 0},
+		{Element{lo: 1 << 63}, Element{hi: 1 << 63}, 0},
+	} {
+		if got := tc.x.Equal(tc.y); got != tc.want {
+			t.Fatalf("#%d: expected %d, got %d", i, tc.want, got)
+		}
+	}
+}
+
+// TestInv tests that x * 1/x = 1.
+func TestInv(t *testing.T) {
+	runTests(t, testInv)
+}
+
+func testInv(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1000; i++ {
+		x := randElem(rng)
+		if x.IsZero() == 1 {
+			continue
+		}
+		var z Element
+		z.Inv(x)
+		z.Mul(z, x)
+		if z.Equal(One()) != 1 {
+			t.Fatalf("%v*1/%v: expected %v, got %v", x, x, One(), z)
+		}
+	}
+
+	var z Element
+	if z.Inv(Element{}).IsZero() != 1 {
+		t.Fatalf("1/0: expected 0, got %v", z)
+	}
+}
+
+// TestRand tests Rand and RandNonZero.
+func TestRand(t *testing.T) {
+	x, err := Rand(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := Rand(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Equal(y) == 1 {
+		t.Fatalf("%v == %v", x, y)
+	}
+
+	zero := bytes.NewReader(make([]byte, 16*1000))
+	if _, err := RandNonZero(zero); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := Rand(bytes.NewReader(nil)); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	want := One()
+	r := bytes.NewReader(append(make([]byte, 16), want.Bytes()...))
+	got, err := RandNonZero(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Equal(want) != 1 {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// TestSquare tests that Square(x) = x*x and Sqrt(x^2) = x.
+func TestSquare(t *testing.T) {
+	runTests(t, testSquare)
+}
+
+func testSquare(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		x := randElem(rng)
+
+		var want, got Element
+		want.Mul(x, x)
+		got.Square(x)
+		if got.Equal(want) != 1 {
+			t.Fatalf("%v^2: expected %v, got %v", x, want, got)
+		}
+
+		got.Sqrt(got)
+		if got.Equal(x) != 1 {
+			t.Fatalf("sqrt(%v^2): expected %v, got %v", x, x, got)
+		}
+
+		got.Sqrt(x)
+		got.Square(got)
+		if got.Equal(x) != 1 {
+			t.Fatalf("sqrt(%v)^2: expected %v, got %v", x, x, got)
+		}
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	x, _ := Rand(nil)
+	for i := 0; i < b.N; i++ {
+		x.Mul(x, x)
+	}
+	elemSink = x
+}
+
+func BenchmarkSquare(b *testing.B) {
+	x, _ := Rand(nil)
+	for i := 0; i < b.N; i++ {
+		x.Square(x)
+	}
+	elemSink = x
+}
+
+func BenchmarkSqrt(b *testing.B) {
+	x, _ := Rand(nil)
+	for i := 0; i < b.N; i++ {
+		x.Sqrt(x)
+	}
+	elemSink = x
+}
+
+func BenchmarkInv(b *testing.B) {
+	x, _ := Rand(nil)
+	for i := 0; i < b.N; i++ {
+		x.Inv(x)
+	}
+	elemSink = x
+}
+
+// TestText tests MarshalText, UnmarshalText, and ParseElement.
+func TestText(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1000; i++ {
+		x := randElem(rng)
+		text, err := x.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := hex.EncodeToString(x.Bytes()); string(text) != want {
+			t.Fatalf("#%d: expected %q, got %q", i, want, text)
+		}
+		y, err := ParseElement(string(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Equal(y) != 1 {
+			t.Fatalf("#%d: expected %v, got %v", i, x, y)
+		}
+	}
+
+	x, err := ParseElement("01000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Element{lo: 1}); x.Equal(want) != 1 {
+		t.Fatalf("expected %v, got %v", want, x)
+	}
+
+	for _, s := range []string{
+		"",
+		"0100000000000000000000000000000",
+		"010000000000000000000000000000000",
+		"0100000000000000000000000000000z",
+	} {
+		if _, err := ParseElement(s); err == nil {
+			t.Fatalf("%q: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gf128
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// POLYVAL and GHASH operate in isomorphic fields. Per RFC 8452
+// appendix A,
+//
+//    POLYVAL(H, X_1, ..., X_n) =
+//        ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)),
+//            ByteReverse(X_1), ..., ByteReverse(X_n)))
+//
+// The map
+//
+//    e -> mulX_GHASH(ByteReverse(e))
+//
+// is a field isomorphism from this package's elements
+// (including its dot-based multiplication) to GHASH's field.
+// Its inverse is
+//
+//    g -> mulX_POLYVAL(ByteReverse(g)).
+//
+// See https://datatracker.ietf.org/doc/html/rfc8452#appendix-A
+
+// ToGHASHRepresentation returns z as a 16-byte element in
+// GHASH's field.
+//
+// The result can be combined with other GHASH values using
+// GHASH's field operations. In particular,
+//
+//    ToGHASHRepresentation(x*y) =
+//        ToGHASHRepresentation(x) • ToGHASHRepresentation(y)
+//
+// where • is GHASH multiplication.
+func (z Element) ToGHASHRepresentation() []byte {
+	// GHASH's element ByteReverse(z) loaded big-endian.
+	//
+	// Because of the bit ordering, doubling is a right shift.
+	// See internal/gcm.
+	lo, hi := z.hi, z.lo
+	msb := -(hi & 1)
+	hi = hi>>1 | lo<<63
+	lo >>= 1
+	lo ^= 0xe100000000000000 & msb
+
+	b := make([]byte, Size)
+	binary.BigEndian.PutUint64(b[0:8], lo)
+	binary.BigEndian.PutUint64(b[8:16], hi)
+	return b
+}
+
+// FromGHASHRepresentation returns the element corresponding to
+// the 16-byte GHASH field element b.
+//
+// It is the inverse of ToGHASHRepresentation.
+func FromGHASHRepresentation(b []byte) (Element, error) {
+	if len(b) != Size {
+		return Element{}, fmt.Errorf("gf128: invalid element size: %d", len(b))
+	}
+	// ByteReverse(b) loaded little-endian.
+	z := Element{
+		lo: binary.BigEndian.Uint64(b[8:16]),
+		hi: binary.BigEndian.Uint64(b[0:8]),
+	}
+	return z.mulx(), nil
+}
+
+// mulx returns z*x.
+//
+// This is mulX_POLYVAL from RFC 8452, not multiplication by
+// the element encoded as 0x02.
+func (z Element) mulx() Element {
+	// h := z >> 127
+	h := z.hi >> (127 - 64)
+
+	// z <<= 1
+	hi := z.hi<<1 | z.lo>>(64-1)
+	lo := z.lo << 1
+
+	// v ^= h ^ (h << 127) ^ (h << 126) ^ (h << 121)
+	lo ^= h
+	hi ^= h << (127 - 64) // h << 127
+	hi ^= h << (126 - 64) // h << 126
+	hi ^= h << (121 - 64) // h << 121
+
+	return Element{hi: hi, lo: lo}
+}
@@ -0,0 +1,73 @@
+package gf128
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval/internal/gcm"
+)
+
+// TestGHASHRepresentation tests that ToGHASHRepresentation is
+// a field isomorphism and that FromGHASHRepresentation is its
+// inverse.
+func TestGHASHRepresentation(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 10000; i++ {
+		x, y := randElem(rng), randElem(rng)
+
+		// GHASH(H, X_1) = X_1 • H
+		g := gcm.New(y.ToGHASHRepresentation())
+		g.UpdateBlocks(x.ToGHASHRepresentation())
+		want := g.Sum(nil)
+
+		var z Element
+		z.Mul(x, y)
+		if got := z.ToGHASHRepresentation(); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+
+		z, err := FromGHASHRepresentation(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var xy Element
+		xy.Mul(x, y)
+		if z.Equal(xy) != 1 {
+			t.Fatalf("#%d: expected %v, got %v", i, xy, z)
+		}
+	}
+
+	if got := One().ToGHASHRepresentation(); !bytes.Equal(got, unhex("80000000000000000000000000000000")) {
+		t.Fatalf("expected GHASH's 1, got %x", got)
+	}
+}
+
+// TestMulxRFCVectors tests mulx over the set of vectors from
+// RFC 8452.
+//
+// See https://datatracker.ietf.org/doc/html/rfc8452#appendix-A
+func TestMulxRFCVectors(t *testing.T) {
+	for i, tc := range []struct {
+		input  []byte
+		output []byte
+	}{
+		{
+			input:  unhex("01000000000000000000000000000000"),
+			output: unhex("02000000000000000000000000000000"),
+		},
+		{
+			input:  unhex("9c98c04df9387ded828175a92ba652d8"),
+			output: unhex("3931819bf271fada0503eb52574ca572"),
+		},
+	} {
+		x, _ := NewElement(tc.input)
+		want := tc.output
+		if got := x.mulx().Bytes(); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %#x, got %#x", i, want, got)
+		}
+	}
+}
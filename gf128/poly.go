@@ -0,0 +1,71 @@
+package gf128
+
+import "errors"
+
+// Eval returns the polynomial
+//
+//    coeffs[0] + coeffs[1]*x + ... + coeffs[n-1]*x^(n-1)
+//
+// evaluated at x = point.
+//
+// It uses Horner's method, so it performs len(coeffs)-1
+// multiplications. If coeffs is empty, Eval returns zero.
+func Eval(point Element, coeffs []Element) Element {
+	if len(coeffs) == 0 {
+		return Element{}
+	}
+	y := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		mul(&y, &y, &point)
+		y.lo ^= coeffs[i].lo
+		y.hi ^= coeffs[i].hi
+	}
+	return y
+}
+
+// Point is a point (X, Y) on a polynomial.
+type Point struct {
+	X, Y Element
+}
+
+// Interpolate returns f(0) where f is the unique polynomial of
+// degree len(points)-1 passing through each point.
+//
+// It is suitable for recovering the secret from Shamir secret
+// shares. Each X must be unique.
+//
+// Interpolate runs in time proportional to len(points)^2 and
+// only leaks the X coordinates via timing.
+func Interpolate(points []Point) (Element, error) {
+	if len(points) == 0 {
+		return Element{}, errors.New("gf128: no points")
+	}
+
+	// Compute the Lagrange basis polynomials at x = 0:
+	//
+	//    l_j(0) = prod_{m!=j} x_m / (x_m - x_j)
+	//
+	// and sum y_j * l_j(0).
+	var sum Element
+	for j, pj := range points {
+		num, den := One(), One()
+		for m, pm := range points {
+			if m == j {
+				continue
+			}
+			var d Element
+			d.Add(pm.X, pj.X)
+			if d.IsZero() == 1 {
+				return Element{}, errors.New("gf128: duplicate x-coordinate")
+			}
+			num.Mul(num, pm.X)
+			den.Mul(den, d)
+		}
+		var l Element
+		l.Inv(den)
+		l.Mul(l, num)
+		l.Mul(l, pj.Y)
+		sum.Add(sum, l)
+	}
+	return sum, nil
+}
@@ -0,0 +1,82 @@
+package gf128
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestEval tests Eval against a naive sum of powers.
+func TestEval(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1000; i++ {
+		point := randElem(rng)
+		coeffs := make([]Element, rng.Intn(20))
+		for j := range coeffs {
+			coeffs[j] = randElem(rng)
+		}
+
+		var want Element
+		pow := One()
+		for _, c := range coeffs {
+			var term Element
+			term.Mul(c, pow)
+			want.Add(want, term)
+			pow.Mul(pow, point)
+		}
+
+		if got := Eval(point, coeffs); got.Equal(want) != 1 {
+			t.Fatalf("#%d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+// TestEvalEmpty tests that Eval returns zero for the empty
+// polynomial.
+func TestEvalEmpty(t *testing.T) {
+	if got := Eval(One(), nil); got.IsZero() != 1 {
+		t.Fatalf("expected 0, got %v", got)
+	}
+}
+
+// TestInterpolate tests that Interpolate recovers the constant
+// term of a random polynomial.
+func TestInterpolate(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 100; i++ {
+		coeffs := make([]Element, rng.Intn(10)+1)
+		for j := range coeffs {
+			coeffs[j] = randElem(rng)
+		}
+		points := make([]Point, len(coeffs))
+		for j := range points {
+			// Distinct with overwhelming probability.
+			x := randElem(rng)
+			points[j] = Point{X: x, Y: Eval(x, coeffs)}
+		}
+
+		got, err := Interpolate(points)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		if want := coeffs[0]; got.Equal(want) != 1 {
+			t.Fatalf("#%d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+// TestInterpolateDuplicate tests that Interpolate rejects
+// duplicate x-coordinates.
+func TestInterpolateDuplicate(t *testing.T) {
+	x := One()
+	points := []Point{{X: x}, {X: x}}
+	if _, err := Interpolate(points); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := Interpolate(nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
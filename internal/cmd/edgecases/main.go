@@ -0,0 +1,224 @@
+// Command edgecases generates POLYVAL test vectors for inputs
+// with awkward shapes.
+//
+// Usage:
+//
+//    go run ./internal/cmd/edgecases [-out file]
+//
+// The vectors cover:
+//
+//   - every length up to nine blocks and the lengths around
+//     each stride used by the block kernels;
+//   - messages of one repeated block;
+//   - multi-megabyte messages;
+//   - keys with high bits set.
+//
+// To keep the output small, each message is stored as a pattern
+// that is repeated to the message's length. The vectors use a
+// fixed seed, so the output only changes when the generator
+// does. Every tag is computed both with this module and with an
+// independent GHASH implementation, and the command fails if
+// they disagree.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/bits"
+	"math/rand"
+	"os"
+
+	"github.com/ericlagergren/polyval"
+	"github.com/ericlagergren/polyval/internal/gcm"
+)
+
+// testVector is a test vector as stored in the output.
+type testVector struct {
+	Comment string `json:"comment"`
+	Key     string `json:"key"`
+	// Pattern is repeated and truncated to Length bytes to
+	// form the message.
+	Pattern string `json:"pattern"`
+	Length  int    `json:"length"`
+	Tag     string `json:"tag"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("edgecases: ")
+
+	out := flag.String("out", "", "output file (default stdout)")
+	flag.Parse()
+
+	buf, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(buf)
+	} else {
+		err = os.WriteFile(*out, buf, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// expand returns the message described by pattern and length.
+func expand(pattern []byte, length int) []byte {
+	msg := make([]byte, 0, length)
+	for len(msg) < length {
+		msg = append(msg, pattern...)
+	}
+	return msg[:length]
+}
+
+// generate returns the vectors as JSON.
+func generate() ([]byte, error) {
+	rng := rand.New(rand.NewSource(8452))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	fill := func(n int, c byte) []byte {
+		return bytes.Repeat([]byte{c}, n)
+	}
+	top := make([]byte, 16)
+	top[15] = 0x80
+	highNibbles := fill(16, 0xf0)
+	highNibbles[0] = 0xf1
+
+	type vector struct {
+		comment string
+		key     []byte
+		pattern []byte
+		length  int
+	}
+	var vecs []vector
+
+	// Lengths around the strides.
+	for _, n := range []int{
+		1, 2, 3, 4, 5, 6, 7, 8, 9,
+		15, 16, 17, 31, 32, 33,
+		63, 64, 65, 127, 128, 129,
+	} {
+		vecs = append(vecs, vector{
+			comment: fmt.Sprintf("%d random blocks", n),
+			key:     random(16),
+			pattern: random(16 * n),
+			length:  16 * n,
+		})
+	}
+
+	// High-bit keys.
+	for _, k := range []struct {
+		name string
+		key  []byte
+	}{
+		{"x^127", top},
+		{"high bit of every byte", fill(16, 0x80)},
+		{"high nibble of every byte", highNibbles},
+		{"all ones", fill(16, 0xff)},
+	} {
+		for _, n := range []int{1, 8, 16, 17} {
+			vecs = append(vecs, vector{
+				comment: fmt.Sprintf("key %s, %d random blocks", k.name, n),
+				key:     k.key,
+				pattern: random(16 * n),
+				length:  16 * n,
+			})
+		}
+	}
+
+	// Repeated identical blocks.
+	for _, b := range []struct {
+		name  string
+		block []byte
+	}{
+		{"zero", fill(16, 0)},
+		{"all ones", fill(16, 0xff)},
+		{"random", random(16)},
+	} {
+		for _, n := range []int{7, 8, 9, 15, 16, 17, 1000} {
+			vecs = append(vecs, vector{
+				comment: fmt.Sprintf("%d %s blocks", n, b.name),
+				key:     random(16),
+				pattern: b.block,
+				length:  16 * n,
+			})
+		}
+	}
+
+	// Multi-megabyte messages. The pattern is not a multiple
+	// of any stride, so the blocks at each stride boundary
+	// differ.
+	for _, n := range []int{1 << 22, 1<<22 + 16*7, 3<<20 + 16*17} {
+		vecs = append(vecs, vector{
+			comment: fmt.Sprintf("%d bytes", n),
+			key:     random(16),
+			pattern: random(16 * 33),
+			length:  n,
+		})
+	}
+
+	var out []testVector
+	for _, v := range vecs {
+		msg := expand(v.pattern, v.length)
+		tag, err := sum(v.key, msg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.comment, err)
+		}
+		out = append(out, testVector{
+			Comment: v.comment,
+			Key:     hex.EncodeToString(v.key),
+			Pattern: hex.EncodeToString(v.pattern),
+			Length:  v.length,
+			Tag:     hex.EncodeToString(tag),
+		})
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sum returns the POLYVAL hash of msg after checking it against
+// GHASH.
+func sum(key, msg []byte) ([]byte, error) {
+	want := polyval.Sum(key, msg)
+
+	// Per RFC 8452 appendix A,
+	//
+	//    POLYVAL(H, X_1, ..., X_n) =
+	//        ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)),
+	//            ByteReverse(X_1), ..., ByteReverse(X_n)))
+	g := gcm.New(gcm.Mulx(byteRev(key)))
+	for i := 0; i < len(msg); i += 16 {
+		g.UpdateBlocks(byteRev(msg[i : i+16]))
+	}
+	if got := byteRev(g.Sum(nil)); !bytes.Equal(got, want[:]) {
+		return nil, fmt.Errorf("POLYVAL %x != GHASH %x", want, got)
+	}
+	return want[:], nil
+}
+
+// byteRev returns the 16-byte string s with its bytes reversed.
+func byteRev(s []byte) []byte {
+	lo := bits.ReverseBytes64(binary.LittleEndian.Uint64(s[0:8]))
+	hi := bits.ReverseBytes64(binary.LittleEndian.Uint64(s[8:16]))
+	r := make([]byte, 16)
+	binary.LittleEndian.PutUint64(r[0:8], hi)
+	binary.LittleEndian.PutUint64(r[8:16], lo)
+	return r
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerate tests that the vectors in testdata are up to
+// date.
+func TestGenerate(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("..", "..", "..", "testdata", "edgecases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("testdata/edgecases.json is out of date")
+	}
+}
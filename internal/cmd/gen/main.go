@@ -2,9 +2,11 @@ package main
 
 import (
 	"bytes"
+	_ "embed"
 	"fmt"
 	"go/format"
 	"os"
+	"text/template"
 )
 
 func main() {
@@ -12,6 +14,8 @@ func main() {
 	switch name := os.Args[1]; name {
 	case "ctmul":
 		err = ctmul()
+	case "arm64":
+		err = arm64()
 	default:
 		panic("unknown template: " + name)
 	}
@@ -32,11 +36,16 @@ func ctmul() error {
 		{0x1084210842108421, 0x0842108421084210},
 	}
 
+	pkg := os.Getenv("GOPACKAGE")
+	if pkg == "" {
+		pkg = "polyval"
+	}
+
 	var b bytes.Buffer
 	b.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\n")
-	b.WriteString("package polyval\n")
+	fmt.Fprintf(&b, "package %s\n", pkg)
 	b.WriteString("import \"math/bits\"\n")
-	b.WriteString("// ctmulGeneric returns the constant time 128-bit product of \n")
+	b.WriteString("// ctmulGeneric64 returns the constant time 128-bit product of \n")
 	b.WriteString("// x and y in GF(2^128).\n")
 	b.WriteString("//\n")
 	b.WriteString("// The idea comes from Thomas Pornin's constant-time blog post\n")
@@ -45,7 +54,7 @@ func ctmul() error {
 	b.WriteString("//\n")
 	b.WriteString("// See https://www.bearssl.org/constanttime.html\n")
 	b.WriteString("// See https://timtaubert.de/blog/2017/06/verified-binary-multiplication-for-ghash/\n")
-	b.WriteString("func ctmulGeneric(x, y uint64) (z1, z0 uint64) {\n")
+	b.WriteString("func ctmulGeneric64(x, y uint64) (z1, z0 uint64) {\n")
 
 	b.WriteString("// Split both x and y into 5 words with four-bit holes.\n")
 	for i := 0; i < 5; i++ {
@@ -115,3 +124,37 @@ func ctmul() error {
 	}
 	return os.WriteFile("zctmul.go", buf, 0644)
 }
+
+//go:embed arm64.tmpl
+var arm64Tmpl string
+
+// arm64 generates field_arm64.s.
+//
+// The kernels that use the SHA-3 extensions are identical to
+// the base kernels except for the macros used to combine and
+// reduce the products, so both are generated from the same
+// template.
+func arm64() error {
+	t, err := template.New("arm64").Parse(arm64Tmpl)
+	if err != nil {
+		return err
+	}
+	type kernel struct {
+		// Name is the suffix of the function name.
+		Name string
+		// Macro is the suffix of the KARATSUBA_2 and REDUCE
+		// macros.
+		Macro string
+	}
+	kernels := []kernel{
+		{Name: "", Macro: ""},
+		{Name: "SHA3", Macro: "_SHA3"},
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\n")
+	if err := t.Execute(&b, kernels); err != nil {
+		return err
+	}
+	return os.WriteFile("field_arm64.s", b.Bytes(), 0644)
+}
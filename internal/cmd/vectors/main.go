@@ -0,0 +1,149 @@
+// Command vectors imports the POLYVAL test vectors from
+// github.com/google/hctr2 into testdata.
+//
+// Usage:
+//
+//    go run ./internal/cmd/vectors [-ref commit] [-in file] [-out file]
+//
+// By default it fetches the vectors at a pinned commit. Pass
+// -ref to import a newer version, or -in to convert a local
+// copy. Each vector is checked for well-formed hex and lengths
+// before anything is written.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+// defaultRef is the commit the vectors in testdata were last
+// imported from.
+const defaultRef = "2a80dc7f742127b1f68f02b310975ac7928ae25e"
+
+const urlFormat = "https://raw.githubusercontent.com/google/hctr2/%s/test_vectors/ours/Polyval/Polyval.json"
+
+// vector is a POLYVAL test vector.
+//
+// The field order matches the upstream files so that the output
+// only changes when the vectors do.
+type vector struct {
+	Cipher struct {
+		Cipher  string `json:"cipher"`
+		Lengths struct {
+			Key int `json:"key"`
+		} `json:"lengths"`
+	} `json:"cipher"`
+	Description string `json:"description"`
+	Input       struct {
+		Key     string `json:"key_hex"`
+		Message string `json:"message_hex"`
+	} `json:"input"`
+	Hash string `json:"hash_hex"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("vectors: ")
+
+	ref := flag.String("ref", defaultRef, "google/hctr2 commit or branch to fetch")
+	in := flag.String("in", "", "read the vectors from this file instead of fetching them")
+	out := flag.String("out", "testdata/polyval.json", "output file")
+	flag.Parse()
+
+	var (
+		data []byte
+		err  error
+	)
+	if *in != "" {
+		data, err = os.ReadFile(*in)
+	} else {
+		data, err = fetch(fmt.Sprintf(urlFormat, *ref))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	buf, err := convert(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func fetch(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// convert checks the upstream vectors and re-encodes them in the
+// format used in testdata.
+func convert(data []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	var vecs []vector
+	if err := d.Decode(&vecs); err != nil {
+		return nil, err
+	}
+	if len(vecs) == 0 {
+		return nil, errors.New("no vectors")
+	}
+	for i, v := range vecs {
+		if err := check(v); err != nil {
+			return nil, fmt.Errorf("#%d (%s): %w", i, v.Description, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "    ")
+	if err := e.Encode(vecs); err != nil {
+		return nil, err
+	}
+	// Match the upstream files, which do not end in a newline.
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// check reports whether v is a well-formed POLYVAL vector.
+func check(v vector) error {
+	if v.Cipher.Cipher != "Polyval" {
+		return fmt.Errorf("unexpected cipher %q", v.Cipher.Cipher)
+	}
+	for _, f := range []struct {
+		name string
+		s    string
+		n    int // required length, or 0 for a multiple of 16
+	}{
+		{"key", v.Input.Key, v.Cipher.Lengths.Key},
+		{"message", v.Input.Message, 0},
+		{"hash", v.Hash, 16},
+	} {
+		b, err := hex.DecodeString(f.s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		if f.n != 0 && len(b) != f.n {
+			return fmt.Errorf("%s: expected %d bytes, got %d", f.name, f.n, len(b))
+		}
+		if len(b)%16 != 0 {
+			return fmt.Errorf("%s: length %d is not a multiple of 16", f.name, len(b))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConvert tests that converting the vectors in testdata
+// does not change them.
+func TestConvert(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("..", "..", "..", "testdata", "polyval.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := convert(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("converting testdata/polyval.json changed it")
+	}
+}
+
+// TestConvertInvalid tests that convert rejects malformed
+// vectors.
+func TestConvertInvalid(t *testing.T) {
+	const valid = `{"cipher": {"cipher": "Polyval", "lengths": {"key": 16}},
+		"description": "test",
+		"input": {"key_hex": "01000000000000000000000000000000", "message_hex": ""},
+		"hash_hex": "00000000000000000000000000000000"}`
+	if _, err := convert([]byte("[" + valid + "]")); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		old, new string
+	}{
+		{`"Polyval"`, `"GHASH"`},
+		{`"key_hex": "01`, `"key_hex": "`},
+		{`"message_hex": ""`, `"message_hex": "00"`},
+		{`"hash_hex": "00`, `"hash_hex": "zz`},
+		{`"description"`, `"comment"`},
+	} {
+		s := "[" + strings.Replace(valid, tc.old, tc.new, 1) + "]"
+		if _, err := convert([]byte(s)); err == nil {
+			t.Fatalf("%q -> %q: expected an error", tc.old, tc.new)
+		}
+	}
+	if _, err := convert([]byte("[]")); err == nil {
+		t.Fatal("expected an error for no vectors")
+	}
+}
@@ -0,0 +1,306 @@
+// Command wycheproof exports POLYVAL test vectors in the
+// Wycheproof MAC test schema.
+//
+// Usage:
+//
+//    go run ./internal/cmd/wycheproof [-vectors file] [-out file]
+//
+// The output contains:
+//
+//   - the test vectors from RFC 8452;
+//   - the google/hctr2 vectors in testdata;
+//   - edge cases: special keys, messages of all zeros or all
+//     ones, and lengths around the strides of the block kernels;
+//   - the same vectors with a modified tag, marked invalid.
+//
+// The edge cases use a fixed seed, so the output only changes
+// when the generator does. Every tag is computed both with this
+// module and with an independent GHASH implementation, and the
+// command fails if they disagree.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/bits"
+	"math/rand"
+	"os"
+
+	"github.com/ericlagergren/polyval"
+	"github.com/ericlagergren/polyval/internal/gcm"
+)
+
+// generatorVersion is incremented whenever the output changes.
+const generatorVersion = "1"
+
+type testFile struct {
+	Algorithm        string            `json:"algorithm"`
+	GeneratorVersion string            `json:"generatorVersion"`
+	NumberOfTests    int               `json:"numberOfTests"`
+	Header           []string          `json:"header"`
+	Notes            map[string]string `json:"notes"`
+	Schema           string            `json:"schema"`
+	TestGroups       []testGroup       `json:"testGroups"`
+}
+
+type testGroup struct {
+	KeySize int        `json:"keySize"`
+	TagSize int        `json:"tagSize"`
+	Type    string     `json:"type"`
+	Tests   []testCase `json:"tests"`
+}
+
+type testCase struct {
+	TcID    int      `json:"tcId"`
+	Comment string   `json:"comment"`
+	Key     string   `json:"key"`
+	Msg     string   `json:"msg"`
+	Tag     string   `json:"tag"`
+	Result  string   `json:"result"`
+	Flags   []string `json:"flags"`
+}
+
+var notes = map[string]string{
+	"RFC8452":     "Test vector from RFC 8452, appendix A.",
+	"HCTR2":       "Test vector from github.com/google/hctr2.",
+	"EdgeCase":    "Special key or message, or a message length around the stride of common block kernels.",
+	"ModifiedTag": "The tag has one bit flipped and must be rejected.",
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("wycheproof: ")
+
+	vectors := flag.String("vectors", "testdata/polyval.json", "google/hctr2 vectors to include")
+	out := flag.String("out", "", "output file (default stdout)")
+	flag.Parse()
+
+	hctr2, err := os.ReadFile(*vectors)
+	if err != nil {
+		log.Fatal(err)
+	}
+	buf, err := generate(hctr2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(buf)
+	} else {
+		err = os.WriteFile(*out, buf, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// vector is a key, message, and comment to compute a tag for.
+type vector struct {
+	key, msg []byte
+	comment  string
+	flag     string
+}
+
+// generate returns the test file as JSON.
+func generate(hctr2 []byte) ([]byte, error) {
+	var vecs []vector
+	for _, v := range []struct {
+		key, msg string
+	}{
+		{
+			key: "25629347589242761d31f826ba4b757b",
+			msg: "4f4f95668c83dfb6401762bb2d01a262",
+		},
+		{
+			key: "25629347589242761d31f826ba4b757b",
+			msg: "4f4f95668c83dfb6401762bb2d01a262" +
+				"d1a24ddd2721d006bbe45f20d3c9f362",
+		},
+	} {
+		vecs = append(vecs, vector{
+			key:     mustUnhex(v.key),
+			msg:     mustUnhex(v.msg),
+			comment: "RFC 8452",
+			flag:    "RFC8452",
+		})
+	}
+
+	var upstream []struct {
+		Description string `json:"description"`
+		Input       struct {
+			Key     string `json:"key_hex"`
+			Message string `json:"message_hex"`
+		} `json:"input"`
+	}
+	if err := json.Unmarshal(hctr2, &upstream); err != nil {
+		return nil, err
+	}
+	for _, v := range upstream {
+		vecs = append(vecs, vector{
+			key:     mustUnhex(v.Input.Key),
+			msg:     mustUnhex(v.Input.Message),
+			comment: "hctr2 " + v.Description,
+			flag:    "HCTR2",
+		})
+	}
+
+	vecs = append(vecs, edgeCases()...)
+
+	g := testGroup{
+		KeySize: 128,
+		TagSize: 128,
+		Type:    "MacTest",
+	}
+	for _, v := range vecs {
+		tag, err := sum(v.key, v.msg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.comment, err)
+		}
+		g.Tests = append(g.Tests, testCase{
+			TcID:    len(g.Tests) + 1,
+			Comment: v.comment,
+			Key:     hex.EncodeToString(v.key),
+			Msg:     hex.EncodeToString(v.msg),
+			Tag:     hex.EncodeToString(tag),
+			Result:  "valid",
+			Flags:   []string{v.flag},
+		})
+	}
+	// Flip one bit of the tag in a few vectors, cycling through
+	// the bit positions.
+	for i, v := range vecs[:16] {
+		tag, _ := sum(v.key, v.msg)
+		tag[i] ^= 1 << (i % 8)
+		g.Tests = append(g.Tests, testCase{
+			TcID:    len(g.Tests) + 1,
+			Comment: v.comment + ", modified tag",
+			Key:     hex.EncodeToString(v.key),
+			Msg:     hex.EncodeToString(v.msg),
+			Tag:     hex.EncodeToString(tag),
+			Result:  "invalid",
+			Flags:   []string{"ModifiedTag"},
+		})
+	}
+
+	f := testFile{
+		Algorithm:        "POLYVAL",
+		GeneratorVersion: generatorVersion,
+		NumberOfTests:    len(g.Tests),
+		Header: []string{
+			"POLYVAL per RFC 8452. Messages are a multiple of 16 bytes.",
+			"Generated by github.com/ericlagergren/polyval/internal/cmd/wycheproof.",
+		},
+		Notes:      notes,
+		Schema:     "mac_test_schema.json",
+		TestGroups: []testGroup{g},
+	}
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// edgeCases returns the generated vectors.
+func edgeCases() []vector {
+	rng := rand.New(rand.NewSource(8452))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	fill := func(n int, c byte) []byte {
+		return bytes.Repeat([]byte{c}, n)
+	}
+	// One is x^0, which is not the multiplicative identity
+	// because POLYVAL multiplies by x^-128.
+	one := fill(16, 0)
+	one[0] = 1
+	top := fill(16, 0)
+	top[15] = 0x80
+
+	var vecs []vector
+	for _, k := range []struct {
+		name string
+		key  []byte
+	}{
+		{"key 1", one},
+		{"key x^127", top},
+		{"key all ones", fill(16, 0xff)},
+		{"random key", random(16)},
+	} {
+		for _, m := range []struct {
+			name string
+			msg  []byte
+		}{
+			{"empty message", nil},
+			{"1 zero block", fill(16, 0)},
+			{"1 block of ones", fill(16, 0xff)},
+			{"17 blocks of ones", fill(16*17, 0xff)},
+		} {
+			vecs = append(vecs, vector{
+				key:     k.key,
+				msg:     m.msg,
+				comment: k.name + ", " + m.name,
+				flag:    "EdgeCase",
+			})
+		}
+	}
+	for _, n := range []int{
+		1, 2, 3, 4, 5, 7, 8, 9, 12, 15, 16, 17,
+		23, 24, 31, 32, 33, 47, 48, 63, 64, 65, 256,
+	} {
+		vecs = append(vecs, vector{
+			key:     random(16),
+			msg:     random(16 * n),
+			comment: fmt.Sprintf("random key, %d random blocks", n),
+			flag:    "EdgeCase",
+		})
+	}
+	return vecs
+}
+
+// sum returns the POLYVAL hash of msg after checking it against
+// GHASH.
+func sum(key, msg []byte) ([]byte, error) {
+	want := polyval.Sum(key, msg)
+
+	// Per RFC 8452 appendix A,
+	//
+	//    POLYVAL(H, X_1, ..., X_n) =
+	//        ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)),
+	//            ByteReverse(X_1), ..., ByteReverse(X_n)))
+	g := gcm.New(gcm.Mulx(byteRev(key)))
+	for i := 0; i < len(msg); i += 16 {
+		g.UpdateBlocks(byteRev(msg[i : i+16]))
+	}
+	if got := byteRev(g.Sum(nil)); !bytes.Equal(got, want[:]) {
+		return nil, fmt.Errorf("POLYVAL %x != GHASH %x", want, got)
+	}
+	return want[:], nil
+}
+
+// byteRev returns the 16-byte string s with its bytes reversed.
+func byteRev(s []byte) []byte {
+	lo := bits.ReverseBytes64(binary.LittleEndian.Uint64(s[0:8]))
+	hi := bits.ReverseBytes64(binary.LittleEndian.Uint64(s[8:16]))
+	r := make([]byte, 16)
+	binary.LittleEndian.PutUint64(r[0:8], hi)
+	binary.LittleEndian.PutUint64(r[8:16], lo)
+	return r
+}
+
+func mustUnhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerate tests that the vectors in testdata are up to
+// date.
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "testdata")
+	hctr2, err := os.ReadFile(filepath.Join(dir, "polyval.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "polyval_wycheproof.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(hctr2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("testdata/polyval_wycheproof.json is out of date")
+	}
+}
@@ -0,0 +1,198 @@
+// Package field implements POLYVAL's field arithmetic.
+//
+// It contains the generic and assembly multiplication kernels
+// shared by the polyval and gf128 packages.
+//
+// [gueron]: https://crypto.stanford.edu/RealWorldCrypto/slides/gueron.pdf
+package field
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+//go:generate go run github.com/ericlagergren/polyval/internal/cmd/gen ctmul
+//go:generate go run github.com/ericlagergren/polyval/internal/cmd/gen arm64
+
+// Element is a little-endian element in GF(2^128).
+type Element struct {
+	Lo, Hi uint64
+}
+
+func (f Element) String() string {
+	return fmt.Sprintf("%#0.16x%0.16x", f.Hi, f.Lo)
+}
+
+// SetBytes sets z to the little-endian element p.
+func (z *Element) SetBytes(p []byte) {
+	z.Lo = binary.LittleEndian.Uint64(p[0:8])
+	z.Hi = binary.LittleEndian.Uint64(p[8:16])
+}
+
+// MulGeneric sets acc = acc*key*x^-128.
+//
+// It never uses the assembly kernels, so it can check them.
+func MulGeneric(acc, key *Element) {
+	x, y := key, acc
+	// We perform schoolbook multiplication of x and y:
+	//
+	// (x1,x0)*(y1,y0) = (x1*y1) + (x1*y0 + x0*y1) + (x0*y0)
+	//                      H         M       M         L
+	//
+	// The middle result (M) can be simplified with Karatsuba
+	// multiplication:
+	//
+	// (x1*y0 + x0*y1)  = (x1+x0) * (y1+x0) + (x1*y1) + (x0*y0)
+	//        M                                  H         L
+	//
+	// This requires one less 64-bit multiplication and reuses
+	// the existing results H and L. (H and L are added to M in
+	// the montgomery reduction; see x1 and x2.)
+	//
+	// This gives us a 256-bit product, X.
+	//
+	// Use the "Shift-XOR reflected reduction" method to reduce
+	// it modulo x^128 + x^127 + x^126 + x^121 + 1.
+	//
+	// This is faster than Gueron's "Fast reduction ..." method
+	// because Go doesn't have CMUL/PMULL intrinsics.
+	//
+	// See [gueron] page 17-19.
+	h1, h0 := ctmulGeneric(x.Hi, y.Hi)           // H
+	l1, l0 := ctmulGeneric(x.Lo, y.Lo)           // L
+	m1, m0 := ctmulGeneric(x.Hi^x.Lo, y.Hi^y.Lo) // M
+
+	m0 ^= l0 ^ h0
+	m1 ^= l1 ^ h1
+
+	l1 ^= m0 ^ (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
+	h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
+	h0 ^= m1 ^ (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
+	h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
+
+	y.Hi = h1
+	y.Lo = h0
+}
+
+// SquareGeneric sets acc = acc*acc*x^-128.
+func SquareGeneric(acc *Element) {
+	// Squaring is linear in characteristic 2: the square of
+	// a polynomial is the polynomial with zeros interleaved
+	// between each bit. The middle Karatsuba term is always
+	// zero, so only the reduction from MulGeneric remains.
+	l1, l0 := spread(acc.Lo)
+	h1, h0 := spread(acc.Hi)
+
+	l1 ^= (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
+	h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
+	h0 ^= (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
+	h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
+
+	acc.Hi = h1
+	acc.Lo = h0
+}
+
+// spread returns the 128-bit value with the bits of x in the
+// even positions.
+func spread(x uint64) (hi, lo uint64) {
+	return spread32(x >> 32), spread32(x & 0xffffffff)
+}
+
+// spread32 returns the 64-bit value with the bits of the 32-bit
+// x in the even positions.
+func spread32(x uint64) uint64 {
+	x = (x | x<<16) & 0x0000ffff0000ffff
+	x = (x | x<<8) & 0x00ff00ff00ff00ff
+	x = (x | x<<4) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// MulBlocksGeneric writes blocks to the running hash acc using
+// the powers of the hash key in pow.
+//
+// pow[len(pow)-1] is the hash key, pow[len(pow)-2] is its
+// square, and so on.
+//
+// Like MulGeneric, it never uses the assembly kernels.
+func MulBlocksGeneric(acc *Element, pow *[16]Element, blocks []byte) {
+	// Unlike the assembly kernels, the generic code does not
+	// benefit from a 16-block stride.
+	mulBlocksGeneric(acc, (*[8]Element)(pow[8:]), blocks)
+}
+
+// ReverseBlocks sets dst to src with the bytes of each 16-byte
+// block reversed.
+//
+// len(src) must be a multiple of 16 and len(dst) must be at
+// least len(src). dst and src may be equal but must not
+// otherwise overlap.
+func ReverseBlocks(dst, src []byte) {
+	if len(src) == 0 {
+		return
+	}
+	_ = dst[len(src)-1]
+	reverseBlocks(dst, src)
+}
+
+func reverseBlocksGeneric(dst, src []byte) {
+	for len(src) >= 16 {
+		lo := binary.LittleEndian.Uint64(src[0:8])
+		hi := binary.LittleEndian.Uint64(src[8:16])
+		binary.LittleEndian.PutUint64(dst[0:8], bits.ReverseBytes64(hi))
+		binary.LittleEndian.PutUint64(dst[8:16], bits.ReverseBytes64(lo))
+		src = src[16:]
+		dst = dst[16:]
+	}
+}
+
+func mulBlocksGeneric64(acc *Element, pow *[8]Element, blocks []byte) {
+	for (len(blocks)/16)%8 != 0 {
+		acc.Lo ^= binary.LittleEndian.Uint64(blocks[0:8])
+		acc.Hi ^= binary.LittleEndian.Uint64(blocks[8:16])
+		MulGeneric(acc, &pow[len(pow)-1])
+		blocks = blocks[16:]
+	}
+
+	const (
+		wide = 16 * len(pow)
+	)
+	for len(blocks) >= wide {
+		var h1, h0, l1, l0, m1, m0 uint64
+		for i, x := range pow {
+			var y Element
+			y.SetBytes(blocks[:16])
+			if i == 0 {
+				y.Lo ^= acc.Lo
+				y.Hi ^= acc.Hi
+			}
+
+			t1, t0 := ctmulGeneric(x.Hi, y.Hi)
+			h1 ^= t1
+			h0 ^= t0
+
+			t1, t0 = ctmulGeneric(x.Lo, y.Lo)
+			l1 ^= t1
+			l0 ^= t0
+
+			t1, t0 = ctmulGeneric(x.Hi^x.Lo, y.Hi^y.Lo)
+			m1 ^= t1
+			m0 ^= t0
+
+			blocks = blocks[16:]
+		}
+
+		m0 ^= l0 ^ h0
+		m1 ^= l1 ^ h1
+
+		l1 ^= m0 ^ (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
+		h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
+		h0 ^= m1 ^ (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
+		h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
+
+		acc.Hi = h1
+		acc.Lo = h0
+	}
+}
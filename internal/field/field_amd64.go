@@ -0,0 +1,122 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"golang.org/x/sys/cpu"
+)
+
+var (
+	// HaveAsm reports whether the assembly kernels are used.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveAsm = cpu.X86.HasPCLMULQDQ
+	// HaveAVX reports whether the assembly kernels use
+	// VEX-encoded instructions.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveAVX = cpu.X86.HasPCLMULQDQ && cpu.X86.HasAVX
+	// HaveAVX512 reports whether the assembly kernels use
+	// AVX-512 and VPCLMULQDQ.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveAVX512 = cpu.X86.HasPCLMULQDQ &&
+		cpu.X86.HasAVX &&
+		cpu.X86.HasAVX2 &&
+		cpu.X86.HasAVX512F &&
+		cpu.X86.HasAVX512VL &&
+		cpu.X86.HasAVX512VPCLMULQDQ
+)
+
+// minAVX512Blocks is the smallest input, in blocks, that
+// MulBlocks gives to the AVX-512 kernel.
+//
+// Both VEX kernels split their input into chunks of 1-3, 4, 8,
+// and 16 blocks by length, but only the AVX-512 kernel writes
+// the 16-block chunks with 512-bit multiplies. Below that, it
+// pays to move the keys into ZMM registers without gaining
+// anything and is up to 15% slower than the AVX kernel.
+const minAVX512Blocks = 16
+
+// kernels returns the kernels supported by the CPU.
+func kernels() []kernel {
+	var ks []kernel
+	if HaveAVX512 {
+		ks = append(ks, kernel{"avx512", func() {
+			HaveAsm, HaveAVX, HaveAVX512 = true, true, true
+		}})
+	}
+	if HaveAVX {
+		ks = append(ks, kernel{"avx", func() {
+			HaveAsm, HaveAVX, HaveAVX512 = true, true, false
+		}})
+	}
+	if HaveAsm {
+		ks = append(ks, kernel{"pclmulqdq", func() {
+			HaveAsm, HaveAVX, HaveAVX512 = true, false, false
+		}})
+	}
+	return append(ks, kernel{"generic", func() {
+		HaveAsm, HaveAVX, HaveAVX512 = false, false, false
+	}})
+}
+
+// Mul sets acc = acc*key*x^-128.
+func Mul(acc, key *Element) {
+	if HaveAsm {
+		if HaveAVX {
+			polymulAVX(acc, key)
+		} else {
+			polymulAsm(acc, key)
+		}
+	} else {
+		MulGeneric(acc, key)
+	}
+}
+
+// Square sets acc = acc*acc*x^-128.
+func Square(acc *Element) {
+	if HaveAsm {
+		if HaveAVX {
+			polymulAVX(acc, acc)
+		} else {
+			polymulAsm(acc, acc)
+		}
+	} else {
+		SquareGeneric(acc)
+	}
+}
+
+// MulBlocks writes blocks to the running hash acc using the
+// powers of the hash key in pow.
+//
+// len(blocks) must be a multiple of 16.
+func MulBlocks(acc *Element, pow *[16]Element, blocks []byte) {
+	if len(blocks) == 0 {
+		return
+	}
+	countBlocks(len(blocks) / 16)
+	if HaveAsm {
+		if HaveAVX512 && len(blocks) >= 16*minAVX512Blocks {
+			polymulBlocksAVX512(acc, pow, &blocks[0], len(blocks)/16)
+		} else if HaveAVX {
+			polymulBlocksAVX(acc, pow, &blocks[0], len(blocks)/16)
+		} else {
+			polymulBlocksAsm(acc, pow, &blocks[0], len(blocks)/16)
+		}
+	} else {
+		MulBlocksGeneric(acc, pow, blocks)
+	}
+}
+
+func reverseBlocks(dst, src []byte) {
+	if HaveAsm {
+		reverseBlocksAsm(&dst[0], &src[0], len(src)/16)
+	} else {
+		reverseBlocksGeneric(dst, src)
+	}
+}
+
+func ctmul(x, y uint64) (z1, z0 uint64) {
+	return ctmulGeneric(x, y)
+}
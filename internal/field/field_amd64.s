@@ -0,0 +1,3443 @@
+// Code generated by command: go run asm.go -out out/field_amd64.s -stubs out/stub_amd64.go -pkg field. DO NOT EDIT.
+
+//go:build gc && !purego && !noasm && !tinygo
+
+#include "textflag.h"
+
+DATA polymask<>+0(SB)/8, $0xc200000000000000
+DATA polymask<>+8(SB)/8, $0xc200000000000000
+GLOBL polymask<>(SB), RODATA|NOPTR, $16
+
+DATA bswapmask<>+0(SB)/8, $0x08090a0b0c0d0e0f
+DATA bswapmask<>+8(SB)/8, $0x0001020304050607
+GLOBL bswapmask<>(SB), RODATA|NOPTR, $16
+
+// func polymulAsm(acc *Element, key *Element)
+// Requires: PCLMULQDQ, SSE, SSE2
+TEXT ·polymulAsm(SB), NOSPLIT, $0-16
+	MOVQ  acc+0(FP), AX
+	MOVQ  key+8(FP), CX
+	MOVOU (AX), X0
+	MOVOU (CX), X1
+	MOVOU polymask<>+0(SB), X2
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X0, X3
+	PXOR      X0, X3
+	PSHUFD    $0xee, X1, X4
+	PXOR      X1, X4
+	PCLMULQDQ $0x00, X3, X4
+	MOVOU     X0, X3
+	PCLMULQDQ $0x11, X1, X3
+	PCLMULQDQ $0x00, X1, X0
+
+	// Karatsuba 2
+	MOVOU      X0, X1
+	SHUFPS     $0x4e, X3, X1
+	MOVOU      X3, X5
+	PXOR       X0, X5
+	PXOR       X1, X5
+	PXOR       X4, X5
+	MOVHLPS    X5, X3
+	PUNPCKLQDQ X5, X0
+
+	// Montgomery reduce
+	MOVOU     X2, X1
+	PCLMULQDQ $0x00, X0, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X0, X1
+	XORPS     X1, X3
+	PCLMULQDQ $0x11, X2, X1
+	PXOR      X3, X1
+	MOVOU     X1, (AX)
+	RET
+
+// func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+// Requires: MMX+, PCLMULQDQ, SSE, SSE2
+TEXT ·polymulBlocksAsm(SB), NOSPLIT, $0-32
+	MOVQ  acc+0(FP), AX
+	MOVQ  pow+8(FP), CX
+	MOVQ  input+16(FP), DX
+	MOVQ  nblocks+24(FP), BX
+	MOVOU polymask<>+0(SB), X0
+	MOVOU (AX), X1
+	CMPQ  BX, $0x08
+	JA    long
+	JE    short8
+	CMPQ  BX, $0x04
+	JA    short5to7
+	JE    short4
+	CMPQ  BX, $0x02
+	JB    short1
+	JE    short2
+
+	// Block 2
+	MOVOU 32(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 208(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short2:
+	// Block 1
+	MOVOU 16(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 224(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short1:
+	// Block 0
+	MOVOU (DX), X2
+	MOVOU 240(CX), X3
+	PXOR  X1, X2
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PCLMULQDQ $0x00, X4, X1
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Karatsuba 2
+	MOVOU      X2, X3
+	SHUFPS     $0x4e, X4, X3
+	MOVOU      X4, X5
+	PXOR       X2, X5
+	PXOR       X3, X5
+	PXOR       X1, X5
+	MOVHLPS    X5, X4
+	PUNPCKLQDQ X5, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short4:
+	// Block 3
+	MOVOU 48(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 192(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short8:
+	// Block 7
+	MOVOU 112(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 6
+	MOVOU 96(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 5
+	MOVOU 80(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 4
+	MOVOU 64(DX), X3
+	MOVOU 192(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 3
+	MOVOU 48(DX), X3
+	MOVOU 176(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 160(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 144(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 128(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short5to7:
+	CMPQ BX, $0x06
+	JB   short5
+	JE   short6
+
+	// Block 6
+	MOVOU 96(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 5
+	MOVOU 80(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 4
+	MOVOU 64(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 3
+	MOVOU 48(DX), X3
+	MOVOU 192(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 176(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 160(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 144(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short5:
+	// Block 4
+	MOVOU 64(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 3
+	MOVOU 48(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 192(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 176(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+short6:
+	// Block 5
+	MOVOU 80(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 4
+	MOVOU 64(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 3
+	MOVOU 48(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 192(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 176(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 160(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	JMP       done
+
+long:
+	MOVQ BX, SI
+	ANDQ $0x03, SI
+	JZ   tail4
+	CMPQ SI, $0x02
+	JB   small1
+	JE   small2
+
+	// Block 2
+	MOVOU 32(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 208(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	ADDQ      $0x30, DX
+	JMP       tail4
+
+small1:
+	// Block 0
+	MOVOU (DX), X2
+	MOVOU 240(CX), X3
+	PXOR  X1, X2
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PCLMULQDQ $0x00, X4, X1
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Karatsuba 2
+	MOVOU      X2, X3
+	SHUFPS     $0x4e, X4, X3
+	MOVOU      X4, X5
+	PXOR       X2, X5
+	PXOR       X3, X5
+	PXOR       X1, X5
+	MOVHLPS    X5, X4
+	PUNPCKLQDQ X5, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	ADDQ      $0x10, DX
+	JMP       tail4
+
+small2:
+	// Block 1
+	MOVOU 16(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 224(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	ADDQ      $0x20, DX
+
+tail4:
+	TESTQ $0x00000004, BX
+	JZ    tail8
+
+	// Block 3
+	MOVOU 48(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 192(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	ADDQ      $0x40, DX
+
+tail8:
+	TESTQ $0x00000008, BX
+	JZ    initWideLoop
+
+	// Block 7
+	MOVOU 112(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 6
+	MOVOU 96(DX), X3
+	MOVOU 224(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 5
+	MOVOU 80(DX), X3
+	MOVOU 208(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 4
+	MOVOU 64(DX), X3
+	MOVOU 192(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 3
+	MOVOU 48(DX), X3
+	MOVOU 176(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 2
+	MOVOU 32(DX), X3
+	MOVOU 160(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 1
+	MOVOU 16(DX), X3
+	MOVOU 144(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X7, X4
+	PXOR      X3, X2
+	PXOR      X8, X5
+
+	// Block 0
+	MOVOU (DX), X3
+	MOVOU 128(CX), X6
+	PXOR  X1, X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X1
+	PXOR      X3, X1
+	PSHUFD    $0xee, X6, X7
+	PXOR      X6, X7
+	PCLMULQDQ $0x00, X1, X7
+	MOVOU     X3, X1
+	PCLMULQDQ $0x11, X6, X1
+	PCLMULQDQ $0x00, X6, X3
+	PXOR      X1, X4
+	PXOR      X3, X2
+	PXOR      X7, X5
+
+	// Karatsuba 2
+	MOVOU      X2, X1
+	SHUFPS     $0x4e, X4, X1
+	MOVOU      X4, X3
+	PXOR       X2, X3
+	PXOR       X1, X3
+	PXOR       X5, X3
+	MOVHLPS    X3, X4
+	PUNPCKLQDQ X3, X2
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X2, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X2, X1
+	XORPS     X1, X4
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X4, X1
+	ADDQ      $0x80, DX
+
+initWideLoop:
+	SHRQ $0x04, BX
+	JZ   done
+
+wideLoop:
+	PREFETCHT0 4096(DX)
+	PREFETCHT0 4160(DX)
+	PREFETCHT0 4224(DX)
+	PREFETCHT0 4288(DX)
+
+	// Block 15
+	MOVOU 240(DX), X2
+	MOVOU 240(CX), X3
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X2, X4
+	PXOR      X2, X4
+	PSHUFD    $0xee, X3, X5
+	PXOR      X3, X5
+	PCLMULQDQ $0x00, X4, X5
+	MOVOU     X2, X4
+	PCLMULQDQ $0x11, X3, X4
+	PCLMULQDQ $0x00, X3, X2
+
+	// Block 7
+	MOVOU 112(DX), X3
+	MOVOU 112(CX), X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X3, X7
+	PXOR      X3, X7
+	PSHUFD    $0xee, X6, X8
+	PXOR      X6, X8
+	PCLMULQDQ $0x00, X7, X8
+	MOVOU     X3, X7
+	PCLMULQDQ $0x11, X6, X7
+	PCLMULQDQ $0x00, X6, X3
+
+	// Block 14
+	MOVOU 224(DX), X6
+	MOVOU 224(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 6
+	MOVOU 96(DX), X6
+	MOVOU 96(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 13
+	MOVOU 208(DX), X6
+	MOVOU 208(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 5
+	MOVOU 80(DX), X6
+	MOVOU 80(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 12
+	MOVOU 192(DX), X6
+	MOVOU 192(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 4
+	MOVOU 64(DX), X6
+	MOVOU 64(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 11
+	MOVOU 176(DX), X6
+	MOVOU 176(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 3
+	MOVOU 48(DX), X6
+	MOVOU 48(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 10
+	MOVOU 160(DX), X6
+	MOVOU 160(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 2
+	MOVOU 32(DX), X6
+	MOVOU 32(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 9
+	MOVOU 144(DX), X6
+	MOVOU 144(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 1
+	MOVOU 16(DX), X6
+	MOVOU 16(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X7
+	PXOR      X6, X3
+	PXOR      X11, X8
+
+	// Block 8
+	MOVOU 128(DX), X6
+	MOVOU 128(CX), X9
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X10
+	PXOR      X6, X10
+	PSHUFD    $0xee, X9, X11
+	PXOR      X9, X11
+	PCLMULQDQ $0x00, X10, X11
+	MOVOU     X6, X10
+	PCLMULQDQ $0x11, X9, X10
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X10, X4
+	PXOR      X6, X2
+	PXOR      X11, X5
+
+	// Block 0
+	MOVOU (DX), X6
+	MOVOU (CX), X9
+	PXOR  X1, X6
+
+	// Karatsuba 1
+	PSHUFD    $0xee, X6, X1
+	PXOR      X6, X1
+	PSHUFD    $0xee, X9, X10
+	PXOR      X9, X10
+	PCLMULQDQ $0x00, X1, X10
+	MOVOU     X6, X1
+	PCLMULQDQ $0x11, X9, X1
+	PCLMULQDQ $0x00, X9, X6
+	PXOR      X1, X7
+	PXOR      X6, X3
+	PXOR      X10, X8
+
+	// Combine accumulators
+	PXOR X4, X7
+	PXOR X2, X3
+	PXOR X5, X8
+
+	// Karatsuba 2
+	MOVOU      X3, X1
+	SHUFPS     $0x4e, X7, X1
+	MOVOU      X7, X2
+	PXOR       X3, X2
+	PXOR       X1, X2
+	PXOR       X8, X2
+	MOVHLPS    X2, X7
+	PUNPCKLQDQ X2, X3
+
+	// Montgomery reduce
+	MOVOU     X0, X1
+	PCLMULQDQ $0x00, X3, X1
+	PSHUFD    $0x4e, X1, X1
+	PXOR      X3, X1
+	XORPS     X1, X7
+	PCLMULQDQ $0x11, X0, X1
+	PXOR      X7, X1
+	ADDQ      $0x00000100, DX
+	SUBQ      $0x01, BX
+	JNZ       wideLoop
+
+done:
+	MOVOU X1, (AX)
+	RET
+
+// func polymulAVX(acc *Element, key *Element)
+// Requires: AVX, PCLMULQDQ
+TEXT ·polymulAVX(SB), NOSPLIT, $0-16
+	MOVQ    acc+0(FP), AX
+	MOVQ    key+8(FP), CX
+	VMOVDQU (AX), X0
+	VMOVDQU (CX), X1
+	VMOVDQU polymask<>+0(SB), X2
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X0, X3
+	VPXOR      X0, X3, X3
+	VPSHUFD    $0xee, X1, X4
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x00, X3, X4, X4
+	VPCLMULQDQ $0x11, X1, X0, X3
+	VPCLMULQDQ $0x00, X1, X0, X0
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X3, X0, X1
+	VPXOR       X0, X3, X5
+	VPXOR       X1, X5, X5
+	VPXOR       X4, X5, X5
+	VMOVHLPS    X5, X3, X3
+	VPUNPCKLQDQ X5, X0, X0
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X0, X2, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X0, X1, X1
+	VPXOR      X1, X3, X3
+	VPCLMULQDQ $0x11, X2, X1, X1
+	VPXOR      X3, X1, X1
+	VMOVDQU    X1, (AX)
+	RET
+
+// func polymulBlocksAVX(acc *Element, pow *[16]Element, input *byte, nblocks int)
+// Requires: AVX, MMX+, PCLMULQDQ
+TEXT ·polymulBlocksAVX(SB), NOSPLIT, $0-32
+	MOVQ    acc+0(FP), AX
+	MOVQ    pow+8(FP), CX
+	MOVQ    input+16(FP), DX
+	MOVQ    nblocks+24(FP), BX
+	VMOVDQU polymask<>+0(SB), X0
+	VMOVDQU (AX), X1
+	CMPQ    BX, $0x08
+	JA      long
+	JE      short8
+	CMPQ    BX, $0x04
+	JA      short5to7
+	JE      short4
+	CMPQ    BX, $0x02
+	JB      short1
+	JE      short2
+
+	// Block 2
+	VMOVDQU 32(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 208(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short2:
+	// Block 1
+	VMOVDQU 16(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 224(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short1:
+	// Block 0
+	VMOVDQU (DX), X2
+	VMOVDQU 240(CX), X3
+	VPXOR   X1, X2, X2
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPCLMULQDQ $0x00, X4, X1, X1
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X3
+	VPXOR       X2, X4, X5
+	VPXOR       X3, X5, X5
+	VPXOR       X1, X5, X5
+	VMOVHLPS    X5, X4, X4
+	VPUNPCKLQDQ X5, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short4:
+	// Block 3
+	VMOVDQU 48(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 192(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short8:
+	// Block 7
+	VMOVDQU 112(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 6
+	VMOVDQU 96(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 5
+	VMOVDQU 80(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 4
+	VMOVDQU 64(DX), X3
+	VMOVDQU 192(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 3
+	VMOVDQU 48(DX), X3
+	VMOVDQU 176(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 160(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 144(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 128(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short5to7:
+	CMPQ BX, $0x06
+	JB   short5
+	JE   short6
+
+	// Block 6
+	VMOVDQU 96(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 5
+	VMOVDQU 80(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 4
+	VMOVDQU 64(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 3
+	VMOVDQU 48(DX), X3
+	VMOVDQU 192(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 176(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 160(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 144(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short5:
+	// Block 4
+	VMOVDQU 64(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 3
+	VMOVDQU 48(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 192(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 176(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+short6:
+	// Block 5
+	VMOVDQU 80(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 4
+	VMOVDQU 64(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 3
+	VMOVDQU 48(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 192(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 176(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 160(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	JMP        done
+
+long:
+	MOVQ BX, SI
+	ANDQ $0x03, SI
+	JZ   tail4
+	CMPQ SI, $0x02
+	JB   small1
+	JE   small2
+
+	// Block 2
+	VMOVDQU 32(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 208(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x30, DX
+	JMP        tail4
+
+small1:
+	// Block 0
+	VMOVDQU (DX), X2
+	VMOVDQU 240(CX), X3
+	VPXOR   X1, X2, X2
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPCLMULQDQ $0x00, X4, X1, X1
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X3
+	VPXOR       X2, X4, X5
+	VPXOR       X3, X5, X5
+	VPXOR       X1, X5, X5
+	VMOVHLPS    X5, X4, X4
+	VPUNPCKLQDQ X5, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x10, DX
+	JMP        tail4
+
+small2:
+	// Block 1
+	VMOVDQU 16(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 224(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x20, DX
+
+tail4:
+	TESTQ $0x00000004, BX
+	JZ    tail8
+
+	// Block 3
+	VMOVDQU 48(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 192(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x40, DX
+
+tail8:
+	TESTQ $0x00000008, BX
+	JZ    initWideLoop
+
+	// Block 7
+	VMOVDQU 112(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 6
+	VMOVDQU 96(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 5
+	VMOVDQU 80(DX), X3
+	VMOVDQU 208(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 4
+	VMOVDQU 64(DX), X3
+	VMOVDQU 192(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 3
+	VMOVDQU 48(DX), X3
+	VMOVDQU 176(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X3
+	VMOVDQU 160(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 144(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 128(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x80, DX
+
+initWideLoop:
+	SHRQ $0x04, BX
+	JZ   done
+
+wideLoop:
+	PREFETCHT0 4096(DX)
+	PREFETCHT0 4160(DX)
+	PREFETCHT0 4224(DX)
+	PREFETCHT0 4288(DX)
+
+	// Block 15
+	VMOVDQU 240(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 7
+	VMOVDQU 112(DX), X3
+	VMOVDQU 112(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+
+	// Block 14
+	VMOVDQU 224(DX), X6
+	VMOVDQU 224(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 6
+	VMOVDQU 96(DX), X6
+	VMOVDQU 96(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 13
+	VMOVDQU 208(DX), X6
+	VMOVDQU 208(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 5
+	VMOVDQU 80(DX), X6
+	VMOVDQU 80(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 12
+	VMOVDQU 192(DX), X6
+	VMOVDQU 192(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 4
+	VMOVDQU 64(DX), X6
+	VMOVDQU 64(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 11
+	VMOVDQU 176(DX), X6
+	VMOVDQU 176(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 3
+	VMOVDQU 48(DX), X6
+	VMOVDQU 48(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 10
+	VMOVDQU 160(DX), X6
+	VMOVDQU 160(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 2
+	VMOVDQU 32(DX), X6
+	VMOVDQU 32(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 9
+	VMOVDQU 144(DX), X6
+	VMOVDQU 144(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 1
+	VMOVDQU 16(DX), X6
+	VMOVDQU 16(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X11, X8, X8
+
+	// Block 8
+	VMOVDQU 128(DX), X6
+	VMOVDQU 128(CX), X9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X10
+	VPXOR      X6, X10, X10
+	VPSHUFD    $0xee, X9, X11
+	VPXOR      X9, X11, X11
+	VPCLMULQDQ $0x00, X10, X11, X11
+	VPCLMULQDQ $0x11, X9, X6, X10
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X10, X4, X4
+	VPXOR      X6, X2, X2
+	VPXOR      X11, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X6
+	VMOVDQU (CX), X9
+	VPXOR   X1, X6, X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X6, X1
+	VPXOR      X6, X1, X1
+	VPSHUFD    $0xee, X9, X10
+	VPXOR      X9, X10, X10
+	VPCLMULQDQ $0x00, X1, X10, X10
+	VPCLMULQDQ $0x11, X9, X6, X1
+	VPCLMULQDQ $0x00, X9, X6, X6
+	VPXOR      X1, X7, X7
+	VPXOR      X6, X3, X3
+	VPXOR      X10, X8, X8
+
+	// Combine accumulators
+	VPXOR X4, X7, X7
+	VPXOR X2, X3, X3
+	VPXOR X5, X8, X8
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X7, X3, X1
+	VPXOR       X3, X7, X2
+	VPXOR       X1, X2, X2
+	VPXOR       X8, X2, X2
+	VMOVHLPS    X2, X7, X7
+	VPUNPCKLQDQ X2, X3, X3
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X3, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X3, X1, X1
+	VPXOR      X1, X7, X7
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X7, X1, X1
+	ADDQ       $0x00000100, DX
+	SUBQ       $0x01, BX
+	JNZ        wideLoop
+
+done:
+	VMOVDQU X1, (AX)
+	RET
+
+// func polymulBlocksAVX512(acc *Element, pow *[16]Element, input *byte, nblocks int)
+// Requires: AVX, AVX2, AVX512F, AVX512VL, MMX+, PCLMULQDQ, VPCLMULQDQ
+TEXT ·polymulBlocksAVX512(SB), NOSPLIT, $0-32
+	MOVQ    acc+0(FP), AX
+	MOVQ    pow+8(FP), CX
+	MOVQ    input+16(FP), DX
+	MOVQ    nblocks+24(FP), BX
+	VMOVDQU polymask<>+0(SB), X0
+	VMOVDQU (AX), X1
+	MOVQ    BX, SI
+	ANDQ    $0x03, SI
+	JZ      tail4
+	CMPQ    SI, $0x02
+	JB      small1
+	JE      small2
+
+	// Block 2
+	VMOVDQU 32(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 1
+	VMOVDQU 16(DX), X3
+	VMOVDQU 224(CX), X6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X7
+	VPXOR      X3, X7, X7
+	VPSHUFD    $0xee, X6, X8
+	VPXOR      X6, X8, X8
+	VPCLMULQDQ $0x00, X7, X8, X8
+	VPCLMULQDQ $0x11, X6, X3, X7
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X7, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X8, X5, X5
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 208(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x30, DX
+	JMP        tail4
+
+small1:
+	// Block 0
+	VMOVDQU (DX), X2
+	VMOVDQU 240(CX), X3
+	VPXOR   X1, X2, X2
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPCLMULQDQ $0x00, X4, X1, X1
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X3
+	VPXOR       X2, X4, X5
+	VPXOR       X3, X5, X5
+	VPXOR       X1, X5, X5
+	VMOVHLPS    X5, X4, X4
+	VPUNPCKLQDQ X5, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x10, DX
+	JMP        tail4
+
+small2:
+	// Block 1
+	VMOVDQU 16(DX), X2
+	VMOVDQU 240(CX), X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X2, X4
+	VPXOR      X2, X4, X4
+	VPSHUFD    $0xee, X3, X5
+	VPXOR      X3, X5, X5
+	VPCLMULQDQ $0x00, X4, X5, X5
+	VPCLMULQDQ $0x11, X3, X2, X4
+	VPCLMULQDQ $0x00, X3, X2, X2
+
+	// Block 0
+	VMOVDQU (DX), X3
+	VMOVDQU 224(CX), X6
+	VPXOR   X1, X3, X3
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, X3, X1
+	VPXOR      X3, X1, X1
+	VPSHUFD    $0xee, X6, X7
+	VPXOR      X6, X7, X7
+	VPCLMULQDQ $0x00, X1, X7, X7
+	VPCLMULQDQ $0x11, X6, X3, X1
+	VPCLMULQDQ $0x00, X6, X3, X3
+	VPXOR      X1, X4, X4
+	VPXOR      X3, X2, X2
+	VPXOR      X7, X5, X5
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X4, X2, X1
+	VPXOR       X2, X4, X3
+	VPXOR       X1, X3, X3
+	VPXOR       X5, X3, X3
+	VMOVHLPS    X3, X4, X4
+	VPUNPCKLQDQ X3, X2, X2
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X2, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X2, X1, X1
+	VPXOR      X1, X4, X4
+	VPCLMULQDQ $0x11, X0, X1, X1
+	VPXOR      X4, X1, X1
+	ADDQ       $0x20, DX
+
+tail4:
+	CMPQ      BX, $0x04
+	JB        done
+	VMOVDQU64 (CX), Z2
+	VMOVDQU64 64(CX), Z3
+	VMOVDQU64 128(CX), Z4
+	VMOVDQU64 192(CX), Z5
+	TESTQ     $0x00000004, BX
+	JZ        tail8
+
+	// Blocks 0-3
+	VMOVDQU64 (DX), Z6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z6, Z7
+	VPXORQ     Z6, Z7, Z7
+	VPSHUFD    $0xee, Z5, Z8
+	VPXORQ     Z5, Z8, Z8
+	VPCLMULQDQ $0x00, Z7, Z8, Z8
+	VPCLMULQDQ $0x11, Z5, Z6, Z7
+	VPCLMULQDQ $0x00, Z5, Z6, Z6
+
+	// Accumulator
+	// Karatsuba 1
+	VPSHUFD    $0xee, X1, X9
+	VPXOR      X1, X9, X9
+	VPSHUFD    $0xee, X5, X10
+	VPXOR      X5, X10, X10
+	VPCLMULQDQ $0x00, X9, X10, X10
+	VPCLMULQDQ $0x11, X5, X1, X9
+	VPCLMULQDQ $0x00, X5, X1, X1
+
+	// Fold lanes
+	VEXTRACTI64X4 $0x01, Z7, Y11
+	VPXOR         Y7, Y11, Y11
+	VEXTRACTI128  $0x01, Y11, X7
+	VPTERNLOGD    $0x96, X9, X11, X7
+	VEXTRACTI64X4 $0x01, Z6, Y9
+	VPXOR         Y6, Y9, Y9
+	VEXTRACTI128  $0x01, Y9, X6
+	VPTERNLOGD    $0x96, X1, X9, X6
+	VEXTRACTI64X4 $0x01, Z8, Y1
+	VPXOR         Y8, Y1, Y1
+	VEXTRACTI128  $0x01, Y1, X8
+	VPTERNLOGD    $0x96, X10, X1, X8
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X7, X6, X1
+	VPXOR       X6, X7, X9
+	VPTERNLOGD  $0x96, X8, X1, X9
+	VMOVHLPS    X9, X7, X7
+	VPUNPCKLQDQ X9, X6, X6
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X6, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X6, X1, X1
+	VPCLMULQDQ $0x11, X0, X1, X6
+	VPTERNLOGD $0x96, X7, X6, X1
+	ADDQ       $0x40, DX
+
+tail8:
+	TESTQ $0x00000008, BX
+	JZ    initWideLoop
+
+	// Blocks 0-3
+	VMOVDQU64 (DX), Z6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z6, Z7
+	VPXORQ     Z6, Z7, Z7
+	VPSHUFD    $0xee, Z4, Z8
+	VPXORQ     Z4, Z8, Z8
+	VPCLMULQDQ $0x00, Z7, Z8, Z8
+	VPCLMULQDQ $0x11, Z4, Z6, Z7
+	VPCLMULQDQ $0x00, Z4, Z6, Z6
+
+	// Blocks 4-7
+	VMOVDQU64 64(DX), Z9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z9, Z10
+	VPXORQ     Z9, Z10, Z10
+	VPSHUFD    $0xee, Z5, Z11
+	VPXORQ     Z5, Z11, Z11
+	VPCLMULQDQ $0x00, Z10, Z11, Z11
+	VPCLMULQDQ $0x11, Z5, Z9, Z10
+	VPCLMULQDQ $0x00, Z5, Z9, Z9
+	VPXORQ     Z10, Z7, Z7
+	VPXORQ     Z9, Z6, Z6
+	VPXORQ     Z11, Z8, Z8
+
+	// Accumulator
+	// Karatsuba 1
+	VPSHUFD    $0xee, X1, X9
+	VPXOR      X1, X9, X9
+	VPSHUFD    $0xee, X4, X10
+	VPXOR      X4, X10, X10
+	VPCLMULQDQ $0x00, X9, X10, X10
+	VPCLMULQDQ $0x11, X4, X1, X9
+	VPCLMULQDQ $0x00, X4, X1, X1
+
+	// Fold lanes
+	VEXTRACTI64X4 $0x01, Z7, Y11
+	VPXOR         Y7, Y11, Y11
+	VEXTRACTI128  $0x01, Y11, X7
+	VPTERNLOGD    $0x96, X9, X11, X7
+	VEXTRACTI64X4 $0x01, Z6, Y9
+	VPXOR         Y6, Y9, Y9
+	VEXTRACTI128  $0x01, Y9, X6
+	VPTERNLOGD    $0x96, X1, X9, X6
+	VEXTRACTI64X4 $0x01, Z8, Y1
+	VPXOR         Y8, Y1, Y1
+	VEXTRACTI128  $0x01, Y1, X8
+	VPTERNLOGD    $0x96, X10, X1, X8
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X7, X6, X1
+	VPXOR       X6, X7, X9
+	VPTERNLOGD  $0x96, X8, X1, X9
+	VMOVHLPS    X9, X7, X7
+	VPUNPCKLQDQ X9, X6, X6
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X6, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X6, X1, X1
+	VPCLMULQDQ $0x11, X0, X1, X6
+	VPTERNLOGD $0x96, X7, X6, X1
+	ADDQ       $0x80, DX
+
+initWideLoop:
+	MOVQ BX, CX
+	SHRQ $0x04, CX
+	JZ   done
+
+wideLoop:
+	PREFETCHT0 4096(DX)
+	PREFETCHT0 4160(DX)
+	PREFETCHT0 4224(DX)
+	PREFETCHT0 4288(DX)
+
+	// Blocks 0-3
+	VMOVDQU64 (DX), Z6
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z6, Z7
+	VPXORQ     Z6, Z7, Z7
+	VPSHUFD    $0xee, Z2, Z8
+	VPXORQ     Z2, Z8, Z8
+	VPCLMULQDQ $0x00, Z7, Z8, Z8
+	VPCLMULQDQ $0x11, Z2, Z6, Z7
+	VPCLMULQDQ $0x00, Z2, Z6, Z6
+
+	// Blocks 4-7
+	VMOVDQU64 64(DX), Z9
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z9, Z10
+	VPXORQ     Z9, Z10, Z10
+	VPSHUFD    $0xee, Z3, Z11
+	VPXORQ     Z3, Z11, Z11
+	VPCLMULQDQ $0x00, Z10, Z11, Z11
+	VPCLMULQDQ $0x11, Z3, Z9, Z10
+	VPCLMULQDQ $0x00, Z3, Z9, Z9
+
+	// Blocks 8-11
+	VMOVDQU64 128(DX), Z12
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z12, Z13
+	VPXORQ     Z12, Z13, Z13
+	VPSHUFD    $0xee, Z4, Z14
+	VPXORQ     Z4, Z14, Z14
+	VPCLMULQDQ $0x00, Z13, Z14, Z14
+	VPCLMULQDQ $0x11, Z4, Z12, Z13
+	VPCLMULQDQ $0x00, Z4, Z12, Z12
+	VPXORQ     Z13, Z7, Z7
+	VPXORQ     Z12, Z6, Z6
+	VPXORQ     Z14, Z8, Z8
+
+	// Blocks 12-15
+	VMOVDQU64 192(DX), Z12
+
+	// Karatsuba 1
+	VPSHUFD    $0xee, Z12, Z13
+	VPXORQ     Z12, Z13, Z13
+	VPSHUFD    $0xee, Z5, Z14
+	VPXORQ     Z5, Z14, Z14
+	VPCLMULQDQ $0x00, Z13, Z14, Z14
+	VPCLMULQDQ $0x11, Z5, Z12, Z13
+	VPCLMULQDQ $0x00, Z5, Z12, Z12
+	VPTERNLOGD $0x96, Z13, Z10, Z7
+	VPTERNLOGD $0x96, Z12, Z9, Z6
+	VPTERNLOGD $0x96, Z14, Z11, Z8
+
+	// Accumulator
+	// Karatsuba 1
+	VPSHUFD    $0xee, X1, X9
+	VPXOR      X1, X9, X9
+	VPSHUFD    $0xee, X2, X10
+	VPXOR      X2, X10, X10
+	VPCLMULQDQ $0x00, X9, X10, X10
+	VPCLMULQDQ $0x11, X2, X1, X9
+	VPCLMULQDQ $0x00, X2, X1, X1
+
+	// Fold lanes
+	VEXTRACTI64X4 $0x01, Z7, Y11
+	VPXOR         Y7, Y11, Y11
+	VEXTRACTI128  $0x01, Y11, X7
+	VPTERNLOGD    $0x96, X9, X11, X7
+	VEXTRACTI64X4 $0x01, Z6, Y9
+	VPXOR         Y6, Y9, Y9
+	VEXTRACTI128  $0x01, Y9, X6
+	VPTERNLOGD    $0x96, X1, X9, X6
+	VEXTRACTI64X4 $0x01, Z8, Y1
+	VPXOR         Y8, Y1, Y1
+	VEXTRACTI128  $0x01, Y1, X8
+	VPTERNLOGD    $0x96, X10, X1, X8
+
+	// Karatsuba 2
+	VSHUFPS     $0x4e, X7, X6, X1
+	VPXOR       X6, X7, X9
+	VPTERNLOGD  $0x96, X8, X1, X9
+	VMOVHLPS    X9, X7, X7
+	VPUNPCKLQDQ X9, X6, X6
+
+	// Montgomery reduce
+	VPCLMULQDQ $0x00, X6, X0, X1
+	VPSHUFD    $0x4e, X1, X1
+	VPXOR      X6, X1, X1
+	VPCLMULQDQ $0x11, X0, X1, X6
+	VPTERNLOGD $0x96, X7, X6, X1
+	ADDQ       $0x00000100, DX
+	SUBQ       $0x01, CX
+	JNZ        wideLoop
+
+done:
+	VMOVDQU X1, (AX)
+	VZEROUPPER
+	RET
+
+// func reverseBlocksAsm(dst *byte, src *byte, nblocks int)
+// Requires: SSE2, SSSE3
+TEXT ·reverseBlocksAsm(SB), NOSPLIT, $0-24
+	MOVQ  dst+0(FP), AX
+	MOVQ  src+8(FP), CX
+	MOVQ  nblocks+16(FP), DX
+	MOVOU bswapmask<>+0(SB), X0
+
+	// Four blocks at a time
+loop4:
+	CMPQ   DX, $0x04
+	JB     loop1
+	MOVOU  (CX), X1
+	PSHUFB X0, X1
+	MOVOU  16(CX), X2
+	PSHUFB X0, X2
+	MOVOU  32(CX), X3
+	PSHUFB X0, X3
+	MOVOU  48(CX), X4
+	PSHUFB X0, X4
+	MOVOU  X1, (AX)
+	MOVOU  X2, 16(AX)
+	MOVOU  X3, 32(AX)
+	MOVOU  X4, 48(AX)
+	ADDQ   $0x40, CX
+	ADDQ   $0x40, AX
+	SUBQ   $0x04, DX
+	JMP    loop4
+
+loop1:
+	TESTQ  DX, DX
+	JZ     done
+	MOVOU  (CX), X1
+	PSHUFB X0, X1
+	MOVOU  X1, (AX)
+	ADDQ   $0x10, CX
+	ADDQ   $0x10, AX
+	SUBQ   $0x01, DX
+	JMP    loop1
+
+done:
+	RET
@@ -0,0 +1,111 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"golang.org/x/sys/cpu"
+)
+
+var (
+	// HaveAsm reports whether the assembly kernels are used.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveAsm = cpu.ARM.HasPMULL || cpu.ARM.HasNEON
+	// HavePMULL reports whether the assembly kernels use
+	// VMULL.P64. Otherwise, they use VMULL.P8.
+	//
+	// It is only modified by tests and kernel selection.
+	HavePMULL = cpu.ARM.HasPMULL
+)
+
+// kernels returns the kernels supported by the CPU.
+func kernels() []kernel {
+	var ks []kernel
+	if HaveAsm && HavePMULL {
+		ks = append(ks, kernel{"pmull", func() {
+			HaveAsm, HavePMULL = true, true
+		}})
+	}
+	if HaveAsm {
+		ks = append(ks, kernel{"neon", func() {
+			HaveAsm, HavePMULL = true, false
+		}})
+	}
+	return append(ks, kernel{"generic", func() {
+		HaveAsm, HavePMULL = false, false
+	}})
+}
+
+// Mul sets acc = acc*key*x^-128.
+func Mul(acc, key *Element) {
+	if HaveAsm {
+		if HavePMULL {
+			polymulAsm(acc, key)
+		} else {
+			polymulAsmP8(acc, key)
+		}
+	} else {
+		MulGeneric(acc, key)
+	}
+}
+
+// Square sets acc = acc*acc*x^-128.
+func Square(acc *Element) {
+	if HaveAsm {
+		if HavePMULL {
+			polymulAsm(acc, acc)
+		} else {
+			polymulAsmP8(acc, acc)
+		}
+	} else {
+		SquareGeneric(acc)
+	}
+}
+
+// MulBlocks writes blocks to the running hash acc using the
+// powers of the hash key in pow.
+//
+// len(blocks) must be a multiple of 16.
+func MulBlocks(acc *Element, pow *[16]Element, blocks []byte) {
+	if len(blocks) == 0 {
+		return
+	}
+	countBlocks(len(blocks) / 16)
+	if HaveAsm {
+		if HavePMULL {
+			polymulBlocksAsm(acc, pow, &blocks[0], len(blocks)/16)
+		} else {
+			polymulBlocksAsmP8(acc, pow, &blocks[0], len(blocks)/16)
+		}
+	} else {
+		MulBlocksGeneric(acc, pow, blocks)
+	}
+}
+
+func ctmul(x, y uint64) (z1, z0 uint64) {
+	if HaveAsm {
+		if HavePMULL {
+			return ctmulAsm(x, y)
+		}
+		return ctmulAsmP8(x, y)
+	}
+	return ctmulGeneric(x, y)
+}
+
+//go:noescape
+func polymulAsm(acc, key *Element)
+
+//go:noescape
+func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func ctmulAsm(x, y uint64) (z1, z0 uint64)
+
+//go:noescape
+func polymulAsmP8(acc, key *Element)
+
+//go:noescape
+func polymulBlocksAsmP8(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func ctmulAsmP8(x, y uint64) (z1, z0 uint64)
@@ -0,0 +1,511 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+#include "textflag.h"
+
+// The following assembly is a translation of the arm64
+// kernels for ARMv7 and ARMv8 cores running in AArch32 state.
+// See field_arm64.s, MulGeneric, and MulBlocksGeneric for more
+// information on the algorithm.
+//
+// The Go assembler does not support NEON on GOARCH=arm, so the
+// vector instructions are encoded by hand. Each macro names the
+// instruction it encodes. Vector registers are given as
+// D-register numbers; a Q register is named by its low half,
+// so Qn is 2*n.
+//
+// Unlike AArch64, the two halves of a Q register are D
+// registers in their own right, so VMULL.P64 can multiply
+// either half directly and Karatsuba does not need to shuffle
+// its operands.
+//
+// There are two sets of kernels. The first uses VMULL.P64 from
+// the ARMv8 crypto extensions. The second uses VMULL.P8, which
+// every NEON implementation has, to build each 64x64-bit
+// multiplication. The Karatsuba and reduction macros take the
+// multiplication macro as an argument.
+
+// The Vd, Vn, and Vm operand fields.
+#define VD(d) ((((d)&15)<<12) | (((d)>>4)<<22))
+#define VN(n) ((((n)&15)<<16) | (((n)>>4)<<7))
+#define VM(m) (((m)&15) | (((m)>>4)<<5))
+
+// VEORQ encodes VEOR Qd, Qn, Qm.
+#define VEORQ(d, n, m) WORD $(0xf3000150 | VD(d) | VN(n) | VM(m))
+
+// VEORD encodes VEOR Dd, Dn, Dm.
+#define VEORD(d, n, m) WORD $(0xf3000110 | VD(d) | VN(n) | VM(m))
+
+// VANDD encodes VAND Dd, Dn, Dm.
+#define VANDD(d, n, m) WORD $(0xf2000110 | VD(d) | VN(n) | VM(m))
+
+// VEXTD encodes VEXT.8 Dd, Dn, Dm, #imm.
+#define VEXTD(d, n, m, imm) WORD $(0xf2b00000 | VD(d) | VN(n) | VM(m) | ((imm)<<8))
+
+// VEXTQ encodes VEXT.8 Qd, Qn, Qm, #imm.
+#define VEXTQ(d, n, m, imm) WORD $(0xf2b00040 | VD(d) | VN(n) | VM(m) | ((imm)<<8))
+
+// VMULL encodes VMULL.P64 Qd, Dn, Dm.
+#define VMULL(d, n, m) WORD $(0xf2a00e00 | VD(d) | VN(n) | VM(m))
+
+// VMULLP8 encodes VMULL.P8 Qd, Dn, Dm.
+#define VMULLP8(d, n, m) WORD $(0xf2800e00 | VD(d) | VN(n) | VM(m))
+
+// VMOVI64 encodes VMOV.I64 Dd, #imm where each bit of |imm|
+// selects a byte of ones.
+#define VMOVI64(d, imm) WORD $(0xf2800e30 | VD(d) | (((imm)>>7)<<24) | ((((imm)>>4)&7)<<16) | ((imm)&15))
+
+// VLD1 encodes VLD1.8 {Dd, Dd+1}, [Rn].
+#define VLD1(d, n) WORD $(0xf4200a0f | VD(d) | ((n)<<16))
+
+// VLD1_POST encodes VLD1.8 {Dd, Dd+1}, [Rn]!.
+#define VLD1_POST(d, n) WORD $(0xf4200a0d | VD(d) | ((n)<<16))
+
+// VST1 encodes VST1.8 {Dd, Dd+1}, [Rn].
+#define VST1(d, n) WORD $(0xf4000a0f | VD(d) | ((n)<<16))
+
+// VST1D encodes VST1.8 {Dd}, [Rn].
+#define VST1D(d, n) WORD $(0xf400070f | VD(d) | ((n)<<16))
+
+// VMOVD encodes VMOV Dm, Rt, Rt2.
+#define VMOVD(m, t, t2) WORD $(0xec400b10 | ((t2)<<16) | ((t)<<12) | VM(m))
+
+#define H 0   // Q0
+#define L 2   // Q1
+#define M 4   // Q2
+#define X 6   // Q3
+#define Y 8   // Q4
+#define T0 10 // Q5
+#define T1 12 // Q6
+#define D 14  // Q7
+
+// POLY is loaded into the high half of |T0| before each
+// reduction from R7 and R8.
+#define POLY 11
+
+#define LOAD_POLY() VMOVD(POLY, 7, 8)
+
+// The VMULL.P8 kernels use D16-D28 as scratch space.
+#define T2 16 // Q8
+#define T3 18 // Q9
+#define T4 20 // Q10
+#define T5 22 // Q11
+#define T6 24 // Q12
+#define K16 26
+#define K32 27
+#define K48 28
+
+#define LOAD_P8_MASKS()    \
+	VMOVI64(K16, 0x03) \
+	VMOVI64(K32, 0x0f) \
+	VMOVI64(K48, 0x3f)
+
+// VMULL_P8 sets |q| = |a|*|b| using eight-bit polynomial
+// multiplication.
+//
+// This is the decomposition from "Fast Software Polynomial
+// Multiplication on ARM Processors Using the NEON Engine" by
+// Câmara, Gouvêa, López, and Dahab. Each of |a| and |b| is
+// rotated by one to four bytes and multiplied bytewise with
+// the other, which produces the cross terms of the full
+// product. The cross terms are masked, shifted into place,
+// and added to D = A*B.
+//
+// |q| may alias |a| or |b|.
+#define VMULL_P8(q, a, b)                                      \
+	VEXTD(T2, a, a, 1)      /* A1 */                       \
+	VEXTD(T6, b, b, 1)      /* B1 */                       \
+	VMULLP8(T2, T2, b)      /* F = A1*B */                 \
+	VEXTD(T3, a, a, 2)      /* A2 */                       \
+	VMULLP8(T6, a, T6)      /* E = A*B1 */                 \
+	VEXTD(T5, b, b, 2)      /* B2 */                       \
+	VMULLP8(T3, T3, b)      /* H = A2*B */                 \
+	VEXTD(T4, a, a, 3)      /* A3 */                       \
+	VMULLP8(T5, a, T5)      /* G = A*B2 */                 \
+	VEORQ(T2, T2, T6)       /* L = E + F */                \
+	VEXTD(T6, b, b, 3)      /* B3 */                       \
+	VMULLP8(T4, T4, b)      /* J = A3*B */                 \
+	VEORD(T2, T2, T2+1)     /* t0 = (L) (P0 + P1) << 8 */  \
+	VANDD(T2+1, T2+1, K48)                                 \
+	VMULLP8(T6, a, T6)      /* I = A*B3 */                 \
+	VEORQ(T3, T3, T5)       /* M = G + H */                \
+	VEXTD(T5, b, b, 4)      /* B4 */                       \
+	VEORD(T3, T3, T3+1)     /* t1 = (M) (P2 + P3) << 16 */ \
+	VANDD(T3+1, T3+1, K32)                                 \
+	VMULLP8(T5, a, T5)      /* K = A*B4 */                 \
+	VEORD(T2, T2, T2+1)                                    \
+	VEORD(T3, T3, T3+1)                                    \
+	VEORQ(T4, T4, T6)       /* N = I + J */                \
+	VEORD(T4, T4, T4+1)     /* t2 = (N) (P4 + P5) << 24 */ \
+	VANDD(T4+1, T4+1, K16)                                 \
+	VEORD(T5, T5, T5+1)     /* t3 = (K) (P6 + P7) << 32 */ \
+	VMOVI64(T5+1, 0)                                       \
+	VEXTQ(T2, T2, T2, 15)                                  \
+	VEORD(T4, T4, T4+1)                                    \
+	VEXTQ(T3, T3, T3, 14)                                  \
+	VMULLP8(q, a, b)        /* D = A*B */                  \
+	VEXTQ(T4, T4, T4, 13)                                  \
+	VEXTQ(T5, T5, T5, 12)                                  \
+	VEORQ(T2, T2, T3)                                      \
+	VEORQ(T4, T4, T5)                                      \
+	VEORQ(q, q, T2)                                        \
+	VEORQ(q, q, T4)
+
+// KARATSUBA_1 performs the first half of Karatsuba
+// multiplication of |X| and |Y|.
+//
+// The results are written directly to |H|, |L|, and |M|.
+//
+//    T0 = {x.lo^x.hi, y.lo^y.hi}
+//
+//    L = x.lo*y.lo
+//    M = x.lo^x.hi * y.lo^y.hi
+//    H = x.hi*y.hi
+//
+#define KARATSUBA_1(MUL)    \
+	VEORD(T0, X, X+1)   \
+	VEORD(T0+1, Y, Y+1) \
+	MUL(M, T0, T0+1)    \
+	MUL(H, X+1, Y+1)    \
+	MUL(L, X, Y)
+
+// KARATSUBA_1_XOR performs the first half of Karatsuba
+// multiplication of |X| and |Y|.
+//
+// The results are XORed with |H|, |L|, and |M|.
+//
+// Clobbers |X|.
+#define KARATSUBA_1_XOR(MUL) \
+	VEORD(T0, X, X+1)    \
+	VEORD(T0+1, Y, Y+1)  \
+	MUL(T1, T0, T0+1)    \
+	MUL(T0, X+1, Y+1)    \
+	MUL(X, X, Y)         \
+	VEORQ(M, M, T1)      \
+	VEORQ(H, H, T0)      \
+	VEORQ(L, L, X)
+
+// KARATSUBA_2 performs the second half of Karatsuba
+// multiplication using |H|, |L|, and |M|.
+//
+// The 256-bit product is written to {L.lo, L.hi, H.lo, H.hi}.
+//
+//    T0 = {l0^h0, l1^h1}
+//
+//    M = {m0, m1} ^ {l0^h0, l1^h1}
+//      = {m0^l0^h0, m1^l1^h1}
+//
+//          x0       x1
+//    L = {l0, l1^m0^l0^h0}
+//
+//               x2       x3
+//    H = {h0^m1^l1^h1, h1}
+//
+#define KARATSUBA_2()      \
+	VEORQ(T0, L, H)    \
+	VEORQ(M, M, T0)    \
+	VEORD(L+1, L+1, M) \
+	VEORD(H, H, M+1)
+
+// REDUCE performs Montgomery reduction on the 256-bit
+// product in |L| and |H|.
+//
+// The result is written to |D|.
+//
+// Perform the Montgomery reduction over the 256-bit X.
+//    [A1:A0] = X0 • 0xc200000000000000
+//    [B1:B0] = [X0 ⊕ A1 : X1 ⊕ A0]
+//    [C1:C0] = B0 • 0xc200000000000000
+//    [D1:D0] = [B0 ⊕ C1 : B1 ⊕ C0]
+// Output: [D1 ⊕ X3 : D0 ⊕ X2]
+//
+// B is computed in place in |L| as {B1, B0}, so the output
+// is L ^ C ^ H.
+#define REDUCE(MUL)         \
+	LOAD_POLY()         \
+	MUL(T1, L, POLY)    \
+	VEORD(L+1, L+1, T1) \
+	VEORD(L, L, T1+1)   \
+	MUL(T1, L+1, POLY)  \
+	VEORQ(D, L, T1)     \
+	VEORQ(D, D, H)
+
+// func polymulAsm(acc, key *Element)
+TEXT ·polymulAsm(SB), NOSPLIT, $0-8
+#define acc_ptr R0
+#define key_ptr R1
+
+	MOVW acc+0(FP), acc_ptr
+	MOVW key+4(FP), key_ptr
+	MOVW $0, R7
+	MOVW $0xc2000000, R8
+
+	VLD1(X, 0) // acc_ptr
+	VLD1(Y, 1) // key_ptr
+
+	KARATSUBA_1(VMULL)
+	KARATSUBA_2()
+	REDUCE(VMULL)
+
+	VST1(D, 0) // acc_ptr
+	RET
+
+#undef acc_ptr
+#undef key_ptr
+
+// func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+TEXT ·polymulBlocksAsm(SB), NOSPLIT, $0-16
+#define acc_ptr R0
+#define pow_ptr R1
+#define input_ptr R2
+#define remain R3
+#define single R4
+#define key_ptr R5
+
+	MOVW acc+0(FP), acc_ptr
+	MOVW pow+4(FP), pow_ptr
+	MOVW input+8(FP), input_ptr
+	MOVW nblocks+12(FP), remain
+	MOVW $0, R7
+	MOVW $0xc2000000, R8
+
+	VLD1(D, 0) // acc_ptr
+
+	// Handle nblocks%8 blocks one at a time using pow[15].
+	AND.S $7, remain, single
+	BEQ   initWideLoop
+
+	ADD  $(15*16), pow_ptr, key_ptr
+	VLD1(Y, 5) // key_ptr
+
+singleLoop:
+	VLD1_POST(X, 2) // input_ptr
+	VEORQ(X, X, D)
+	KARATSUBA_1(VMULL)
+	KARATSUBA_2()
+	REDUCE(VMULL)
+
+	SUB.S $1, single
+	BNE   singleLoop
+
+initWideLoop:
+	MOVW.S remain>>3, remain
+	BEQ    done
+
+	// The wide loop uses an 8-block stride with pow[8:16].
+	ADD $(8*16), pow_ptr
+
+wideLoop:
+	MOVW pow_ptr, key_ptr
+
+	// Block 0
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	VEORQ(X, X, D)
+	KARATSUBA_1(VMULL)
+
+	// Block 1
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 2
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 3
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 4
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 5
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 6
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	// Block 7
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL)
+
+	KARATSUBA_2()
+	REDUCE(VMULL)
+
+	SUB.S $1, remain
+	BNE   wideLoop
+
+done:
+	VST1(D, 0) // acc_ptr
+	RET
+
+#undef acc_ptr
+#undef pow_ptr
+#undef input_ptr
+#undef remain
+#undef single
+#undef key_ptr
+
+// func ctmulAsm(x, y uint64) (z1, z0 uint64)
+TEXT ·ctmulAsm(SB), NOSPLIT, $0-32
+	// x and y are adjacent, so load them both into Q3.
+	MOVW $x+0(FP), R0
+	VLD1(X, 0)
+	VMULL(Y, X, X+1)
+
+	MOVW $z1+16(FP), R0
+	VST1D(Y+1, 0)
+	MOVW $z0+24(FP), R0
+	VST1D(Y, 0)
+	RET
+
+// func polymulAsmP8(acc, key *Element)
+TEXT ·polymulAsmP8(SB), NOSPLIT, $0-8
+#define acc_ptr R0
+#define key_ptr R1
+
+	MOVW acc+0(FP), acc_ptr
+	MOVW key+4(FP), key_ptr
+	MOVW $0, R7
+	MOVW $0xc2000000, R8
+	LOAD_P8_MASKS()
+
+	VLD1(X, 0) // acc_ptr
+	VLD1(Y, 1) // key_ptr
+
+	KARATSUBA_1(VMULL_P8)
+	KARATSUBA_2()
+	REDUCE(VMULL_P8)
+
+	VST1(D, 0) // acc_ptr
+	RET
+
+#undef acc_ptr
+#undef key_ptr
+
+// func polymulBlocksAsmP8(acc *Element, pow *[16]Element, input *byte, nblocks int)
+TEXT ·polymulBlocksAsmP8(SB), NOSPLIT, $0-16
+#define acc_ptr R0
+#define pow_ptr R1
+#define input_ptr R2
+#define remain R3
+#define single R4
+#define key_ptr R5
+
+	MOVW acc+0(FP), acc_ptr
+	MOVW pow+4(FP), pow_ptr
+	MOVW input+8(FP), input_ptr
+	MOVW nblocks+12(FP), remain
+	MOVW $0, R7
+	MOVW $0xc2000000, R8
+	LOAD_P8_MASKS()
+
+	VLD1(D, 0) // acc_ptr
+
+	// Handle nblocks%8 blocks one at a time using pow[15].
+	AND.S $7, remain, single
+	BEQ   initWideLoop
+
+	ADD  $(15*16), pow_ptr, key_ptr
+	VLD1(Y, 5) // key_ptr
+
+singleLoop:
+	VLD1_POST(X, 2) // input_ptr
+	VEORQ(X, X, D)
+	KARATSUBA_1(VMULL_P8)
+	KARATSUBA_2()
+	REDUCE(VMULL_P8)
+
+	SUB.S $1, single
+	BNE   singleLoop
+
+initWideLoop:
+	MOVW.S remain>>3, remain
+	BEQ    done
+
+	// The wide loop uses an 8-block stride with pow[8:16].
+	ADD $(8*16), pow_ptr
+
+wideLoop:
+	MOVW pow_ptr, key_ptr
+
+	// Block 0
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	VEORQ(X, X, D)
+	KARATSUBA_1(VMULL_P8)
+
+	// Block 1
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 2
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 3
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 4
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 5
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 6
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	// Block 7
+	VLD1_POST(X, 2) // input_ptr
+	VLD1_POST(Y, 5) // key_ptr
+	KARATSUBA_1_XOR(VMULL_P8)
+
+	KARATSUBA_2()
+	REDUCE(VMULL_P8)
+
+	SUB.S $1, remain
+	BNE   wideLoop
+
+done:
+	VST1(D, 0) // acc_ptr
+	RET
+
+#undef acc_ptr
+#undef pow_ptr
+#undef input_ptr
+#undef remain
+#undef single
+#undef key_ptr
+
+// func ctmulAsmP8(x, y uint64) (z1, z0 uint64)
+TEXT ·ctmulAsmP8(SB), NOSPLIT, $0-32
+	// x and y are adjacent, so load them both into Q3.
+	LOAD_P8_MASKS()
+	MOVW $x+0(FP), R0
+	VLD1(X, 0)
+	VMULL_P8(Y, X, X+1)
+
+	MOVW $z1+16(FP), R0
+	VST1D(Y+1, 0)
+	MOVW $z0+24(FP), R0
+	VST1D(Y, 0)
+	RET
@@ -0,0 +1,23 @@
+//go:build arm64 && !openbsd && !windows && gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+)
+
+// hasPMULL reports whether the CPU supports PMULL.
+//
+// x/sys/cpu cannot read the CPU features on darwin, but every
+// arm64 Mac has them.
+func hasPMULL() bool {
+	return runtime.GOOS == "darwin" || cpu.ARM64.HasPMULL
+}
+
+// hasSHA3 reports whether the CPU supports the SHA-3
+// extensions.
+func hasSHA3() bool {
+	return runtime.GOOS == "darwin" || cpu.ARM64.HasSHA3
+}
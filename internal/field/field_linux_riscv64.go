@@ -0,0 +1,57 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// The x/sys/cpu version this module depends on does not know
+// about riscv64, so ask the kernel directly.
+//
+// See https://docs.kernel.org/arch/riscv/hwprobe.html
+const (
+	sysRISCVHWProbe = 258
+
+	hwprobeKeyIMAExt0 = 4
+	hwprobeIMAV       = 1 << 2
+	hwprobeExtZbc     = 1 << 7
+	hwprobeExtZvbc    = 1 << 18
+)
+
+type riscvHWProbePair struct {
+	key   int64
+	value uint64
+}
+
+// hwprobeIMAExt0 returns the extensions supported by every
+// CPU, or zero if the kernel does not support hwprobe.
+func hwprobeIMAExt0() uint64 {
+	pair := riscvHWProbePair{key: hwprobeKeyIMAExt0}
+	_, _, errno := unix.RawSyscall6(sysRISCVHWProbe,
+		uintptr(unsafe.Pointer(&pair)), 1, 0, 0, 0, 0)
+	if errno != 0 || pair.key != hwprobeKeyIMAExt0 {
+		return 0
+	}
+	return pair.value
+}
+
+// hasZbc reports whether every CPU supports the Zbc extension.
+//
+// Kernels older than 6.8 do not report Zbc, so it is assumed
+// to be missing.
+func hasZbc() bool {
+	return hwprobeIMAExt0()&hwprobeExtZbc != 0
+}
+
+// hasZvbc reports whether every CPU supports the V and Zvbc
+// extensions.
+//
+// V is required, not just Zve64x, because the kernel assumes
+// VLEN is at least 128.
+func hasZvbc() bool {
+	ext := hwprobeIMAExt0()
+	return ext&hwprobeIMAV != 0 && ext&hwprobeExtZvbc != 0
+}
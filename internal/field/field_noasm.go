@@ -0,0 +1,33 @@
+//go:build !(amd64 || arm || arm64 || riscv64 || s390x) || !gc || purego || noasm || tinygo
+
+package field
+
+// kernels returns the kernels supported by the CPU.
+func kernels() []kernel {
+	return []kernel{{"generic", func() {}}}
+}
+
+// Mul sets acc = acc*key*x^-128.
+func Mul(acc, key *Element) {
+	MulGeneric(acc, key)
+}
+
+// Square sets acc = acc*acc*x^-128.
+func Square(acc *Element) {
+	SquareGeneric(acc)
+}
+
+// MulBlocks writes blocks to the running hash acc using the
+// powers of the hash key in pow.
+//
+// len(blocks) must be a multiple of 16.
+func MulBlocks(acc *Element, pow *[16]Element, blocks []byte) {
+	if len(blocks) != 0 {
+		countBlocks(len(blocks) / 16)
+	}
+	MulBlocksGeneric(acc, pow, blocks)
+}
+
+func ctmul(x, y uint64) (z1, z0 uint64) {
+	return ctmulGeneric(x, y)
+}
@@ -0,0 +1,57 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// The x/sys/cpu version this module depends on does not read
+// the CPU features on OpenBSD, so read ID_AA64ISAR0_EL1 with
+// sysctl. OpenBSD only allows system calls through libc, so
+// this mirrors what newer versions of x/sys/cpu do.
+const (
+	// From OpenBSD's sys/sysctl.h.
+	ctlMachdep = 7
+
+	// From OpenBSD's machine/cpu.h.
+	cpuIDAA64ISAR0 = 2
+)
+
+//go:linkname syscall_syscall6 syscall.syscall6
+func syscall_syscall6(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err syscall.Errno)
+
+var libc_sysctl_trampoline_addr uintptr
+
+//go:cgo_import_dynamic libc_sysctl sysctl "libc.so"
+
+// isar0 returns ID_AA64ISAR0_EL1, or zero if it cannot be
+// read. OpenBSD 7.1 and later provide it.
+func isar0() uint64 {
+	mib := [2]uint32{ctlMachdep, cpuIDAA64ISAR0}
+	var out uint64
+	n := unsafe.Sizeof(out)
+	_, _, errno := syscall_syscall6(libc_sysctl_trampoline_addr,
+		uintptr(unsafe.Pointer(&mib[0])), uintptr(len(mib)),
+		uintptr(unsafe.Pointer(&out)), uintptr(unsafe.Pointer(&n)),
+		0, 0)
+	if errno != 0 {
+		return 0
+	}
+	return out
+}
+
+// hasPMULL reports whether the CPU supports PMULL.
+func hasPMULL() bool {
+	// ID_AA64ISAR0_EL1.AES is 2 if PMULL is supported.
+	return (isar0()>>4)&0xf >= 2
+}
+
+// hasSHA3 reports whether the CPU supports the SHA-3
+// extensions.
+func hasSHA3() bool {
+	// ID_AA64ISAR0_EL1.SHA3 is 1 if EOR3, RAX1, XAR, and BCAX
+	// are supported.
+	return (isar0()>>32)&0xf >= 1
+}
@@ -0,0 +1,90 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+var (
+	// HaveAsm reports whether the assembly kernels are used.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveAsm = hasZbc()
+	// HaveZvbc reports whether the assembly kernels use the
+	// Zvbc vector extension for long inputs.
+	//
+	// It is only modified by tests and kernel selection.
+	HaveZvbc = HaveAsm && hasZvbc()
+)
+
+// kernels returns the kernels supported by the CPU.
+func kernels() []kernel {
+	var ks []kernel
+	if HaveZvbc {
+		ks = append(ks, kernel{"zvbc", func() {
+			HaveAsm, HaveZvbc = true, true
+		}})
+	}
+	if HaveAsm {
+		ks = append(ks, kernel{"zbc", func() {
+			HaveAsm, HaveZvbc = true, false
+		}})
+	}
+	return append(ks, kernel{"generic", func() {
+		HaveAsm, HaveZvbc = false, false
+	}})
+}
+
+// Mul sets acc = acc*key*x^-128.
+func Mul(acc, key *Element) {
+	if HaveAsm {
+		polymulAsm(acc, key)
+	} else {
+		MulGeneric(acc, key)
+	}
+}
+
+// Square sets acc = acc*acc*x^-128.
+func Square(acc *Element) {
+	if HaveAsm {
+		polymulAsm(acc, acc)
+	} else {
+		SquareGeneric(acc)
+	}
+}
+
+// MulBlocks writes blocks to the running hash acc using the
+// powers of the hash key in pow.
+//
+// len(blocks) must be a multiple of 16.
+func MulBlocks(acc *Element, pow *[16]Element, blocks []byte) {
+	if len(blocks) == 0 {
+		return
+	}
+	countBlocks(len(blocks) / 16)
+	if HaveAsm {
+		if HaveZvbc {
+			polymulBlocksAsmZvbc(acc, pow, &blocks[0], len(blocks)/16)
+		} else {
+			polymulBlocksAsm(acc, pow, &blocks[0], len(blocks)/16)
+		}
+	} else {
+		MulBlocksGeneric(acc, pow, blocks)
+	}
+}
+
+func ctmul(x, y uint64) (z1, z0 uint64) {
+	if HaveAsm {
+		return ctmulAsm(x, y)
+	}
+	return ctmulGeneric(x, y)
+}
+
+//go:noescape
+func polymulAsm(acc, key *Element)
+
+//go:noescape
+func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func polymulBlocksAsmZvbc(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func ctmulAsm(x, y uint64) (z1, z0 uint64)
@@ -0,0 +1,444 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+#include "textflag.h"
+
+// The following assembly uses the Zbc carry-less multiply
+// instructions. See MulGeneric and MulBlocksGeneric for more
+// information on the algorithm.
+//
+// Go's assembler did not learn CLMUL and CLMULH until recently,
+// so they are encoded with WORD. That requires fixed registers,
+// which are assigned as follows:
+//
+//    X5, X6    x.lo, x.hi
+//    X7, X8    y.lo, y.hi
+//    X9, X10   scratch
+//    X17-X22   the 256-bit product {L0, L1, M0, M1, H0, H1}
+//    X23       poly
+//
+// Each 64x64 multiplication is two instructions, so the
+// schoolbook product is used: Karatsuba would save two
+// multiplications but needs extra XORs to form its operands.
+//
+// The input is not guaranteed to be 8-byte aligned, and
+// misaligned loads trap on many RISC-V cores, so unaligned
+// blocks are loaded one byte at a time.
+//
+// polymulBlocksAsmZvbc also uses the Zvbc vector extension to
+// multiply eight blocks at a time. It requires VLEN >= 128 so
+// that eight 64-bit elements fit in a group of four registers.
+// A segment load splits the blocks into
+//
+//    v16 = {x[0].lo, ..., x[7].lo}
+//    v20 = {x[0].hi, ..., x[7].hi}
+//
+// (and likewise pow[8:16] into v8 and v12), and each partial
+// product is summed across the group with VREDXOR. The
+// reduction itself is scalar.
+
+// CLMUL sets rd = (rs1 • rs2) mod 2^64.
+#define CLMUL(rd, rs1, rs2) \
+	WORD $(0x0a001033 | (rs2)<<20 | (rs1)<<15 | (rd)<<7)
+
+// CLMULH sets rd = (rs1 • rs2) >> 64.
+#define CLMULH(rd, rs1, rs2) \
+	WORD $(0x0a003033 | (rs2)<<20 | (rs1)<<15 | (rd)<<7)
+
+// The following vector instructions are encoded by hand for
+// the same reason. |vd|, |vs1|, and |vs2| are register numbers.
+
+// VSETIVLI_8_E64_M4 sets vl = 8, SEW = 64, and LMUL = 4 with
+// the tail-undisturbed and mask-undisturbed policies.
+#define VSETIVLI_8_E64_M4 \
+	WORD $0xc1a47057
+
+// VLSEG2E64 loads 2*vl uint64s from |rs1| and deinterleaves
+// them into the groups starting at |vd| and |vd|+4.
+#define VLSEG2E64(vd, rs1) \
+	WORD $(0x22007007 | (rs1)<<15 | (vd)<<7)
+
+// VMV_S_X sets element 0 of |vd| to |rs1|.
+#define VMV_S_X(vd, rs1) \
+	WORD $(0x42006057 | (rs1)<<15 | (vd)<<7)
+
+// VMV_X_S sets |rd| to element 0 of |vs2|.
+#define VMV_X_S(rd, vs2) \
+	WORD $(0x42002057 | (vs2)<<20 | (rd)<<7)
+
+// VXOR sets vd = vs2 ⊕ vs1.
+#define VXOR(vd, vs2, vs1) \
+	WORD $(0x2e000057 | (vs2)<<20 | (vs1)<<15 | (vd)<<7)
+
+// VREDXOR sets element 0 of |vd| to element 0 of |vs1| XORed
+// with every element of |vs2|.
+#define VREDXOR(vd, vs2, vs1) \
+	WORD $(0x0e002057 | (vs2)<<20 | (vs1)<<15 | (vd)<<7)
+
+// VCLMUL sets vd = (vs2 • vs1) mod 2^64.
+#define VCLMUL(vd, vs2, vs1) \
+	WORD $(0x32002057 | (vs2)<<20 | (vs1)<<15 | (vd)<<7)
+
+// VCLMULH sets vd = (vs2 • vs1) >> 64.
+#define VCLMULH(vd, vs2, vs1) \
+	WORD $(0x36002057 | (vs2)<<20 | (vs1)<<15 | (vd)<<7)
+
+#define L0 X17
+#define L1 X18
+#define M0 X19
+#define M1 X20
+#define H0 X21
+#define H1 X22
+#define poly X23
+#define dlo X24
+#define dhi X25
+
+// MUL computes the 256-bit product of x and y.
+//
+// The results are written directly to L, M, and H.
+#define MUL() \
+	CLMUL(17, 5, 7)  \
+	CLMULH(18, 5, 7) \
+	CLMUL(19, 5, 8)  \
+	CLMULH(20, 5, 8) \
+	CLMUL(21, 6, 8)  \
+	CLMULH(22, 6, 8) \
+	CLMUL(9, 6, 7)   \
+	CLMULH(10, 6, 7) \
+	XOR X9, M0       \
+	XOR X10, M1
+
+// MUL_XOR computes the 256-bit product of x and y.
+//
+// The results are XORed with L, M, and H.
+#define MUL_XOR() \
+	CLMUL(9, 5, 7)   \
+	CLMULH(10, 5, 7) \
+	XOR X9, L0       \
+	XOR X10, L1      \
+	CLMUL(9, 5, 8)   \
+	CLMULH(10, 5, 8) \
+	XOR X9, M0       \
+	XOR X10, M1      \
+	CLMUL(9, 6, 7)   \
+	CLMULH(10, 6, 7) \
+	XOR X9, M0       \
+	XOR X10, M1      \
+	CLMUL(9, 6, 8)   \
+	CLMULH(10, 6, 8) \
+	XOR X9, H0       \
+	XOR X10, H1
+
+// REDUCE performs Montgomery reduction on L, M, and H.
+//
+// The result is written to {dlo, dhi}.
+//
+// First, the middle term is added to the high and low halves:
+//
+//    X = [H1 : H0⊕M1 : L1⊕M0 : L0]
+//
+// Then, perform the Montgomery reduction over the 256-bit X.
+//    [A1:A0] = X0 • 0xc200000000000000
+//    [B1:B0] = [X0 ⊕ A1 : X1 ⊕ A0]
+//    [C1:C0] = B0 • 0xc200000000000000
+//    [D1:D0] = [B0 ⊕ C1 : B1 ⊕ C0]
+// Output: [D1 ⊕ X3 : D0 ⊕ X2]
+#define REDUCE() \
+	XOR    M0, L1            \
+	XOR    M1, H0            \
+	CLMUL(9, 17, 23)         \
+	CLMULH(10, 17, 23)       \
+	XOR    X9, L1            \
+	XOR    X10, L0           \
+	CLMUL(9, 18, 23)         \
+	CLMULH(10, 18, 23)       \
+	XOR    L1, H1            \
+	XOR    X10, H1, dhi      \
+	XOR    L0, H0            \
+	XOR    X9, H0, dlo
+
+// LOAD_ALIGNED loads the block at |off|(|ptr|) into x.
+#define LOAD_ALIGNED(ptr, off) \
+	MOV off(ptr), X5 \
+	MOV off+8(ptr), X6
+
+// LOAD64_UNALIGNED loads the little-endian uint64 at
+// |off|(|ptr|) into |dst| one byte at a time.
+#define LOAD64_UNALIGNED(ptr, off, dst) \
+	MOVBU off+7(ptr), dst \
+	SLL   $8, dst         \
+	MOVBU off+6(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+5(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+4(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+3(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+2(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+1(ptr), X11 \
+	OR    X11, dst        \
+	SLL   $8, dst         \
+	MOVBU off+0(ptr), X11 \
+	OR    X11, dst
+
+// LOAD_UNALIGNED loads the block at |off|(|ptr|) into x.
+#define LOAD_UNALIGNED(ptr, off) \
+	LOAD64_UNALIGNED(ptr, off, X5) \
+	LOAD64_UNALIGNED(ptr, off+8, X6)
+
+// LOAD_KEY loads the key at |off|(|ptr|) into y.
+#define LOAD_KEY(ptr, off) \
+	MOV off(ptr), X7 \
+	MOV off+8(ptr), X8
+
+// SINGLE multiplies d plus one block by y and reduces the
+// result into d.
+#define SINGLE(LOAD) \
+	LOAD(input_ptr, 0) \
+	XOR dlo, X5        \
+	XOR dhi, X6        \
+	MUL()              \
+	REDUCE()
+
+// WIDE multiplies d plus eight blocks by pow[8:16] and reduces
+// the result into d.
+#define WIDE(LOAD) \
+	LOAD(input_ptr, 0*16)         \
+	XOR      dlo, X5              \
+	XOR      dhi, X6              \
+	LOAD_KEY(pow_ptr, 8*16)       \
+	MUL()                         \
+	LOAD(input_ptr, 1*16)         \
+	LOAD_KEY(pow_ptr, 9*16)       \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 2*16)         \
+	LOAD_KEY(pow_ptr, 10*16)      \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 3*16)         \
+	LOAD_KEY(pow_ptr, 11*16)      \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 4*16)         \
+	LOAD_KEY(pow_ptr, 12*16)      \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 5*16)         \
+	LOAD_KEY(pow_ptr, 13*16)      \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 6*16)         \
+	LOAD_KEY(pow_ptr, 14*16)      \
+	MUL_XOR()                     \
+	LOAD(input_ptr, 7*16)         \
+	LOAD_KEY(pow_ptr, 15*16)      \
+	MUL_XOR()                     \
+	REDUCE()
+
+// SUM_INTO sets the scalar register number |rd| to the XOR of
+// every element of the vector register group |vs2|.
+#define SUM_INTO(rd, vs2) \
+	VREDXOR(1, vs2, 0) \
+	VMV_X_S(rd, 1)
+
+// WIDE_ZVBC multiplies d plus the eight blocks at |input_ptr|
+// by pow[8:16], which must be in v8 and v12, and reduces the
+// result into d.
+#define WIDE_ZVBC() \
+	VLSEG2E64(16, 30)   \
+	VMV_X_S(9, 16)      \
+	XOR     dlo, X9     \
+	VMV_S_X(16, 9)      \
+	VMV_X_S(9, 20)      \
+	XOR     dhi, X9     \
+	VMV_S_X(20, 9)      \
+	VCLMUL(24, 16, 8)   \
+	SUM_INTO(17, 24)    \
+	VCLMULH(24, 16, 8)  \
+	SUM_INTO(18, 24)    \
+	VCLMUL(24, 16, 12)  \
+	VCLMUL(28, 20, 8)   \
+	VXOR(24, 24, 28)    \
+	SUM_INTO(19, 24)    \
+	VCLMULH(24, 16, 12) \
+	VCLMULH(28, 20, 8)  \
+	VXOR(24, 24, 28)    \
+	SUM_INTO(20, 24)    \
+	VCLMUL(24, 20, 12)  \
+	SUM_INTO(21, 24)    \
+	VCLMULH(24, 20, 12) \
+	SUM_INTO(22, 24)    \
+	REDUCE()
+
+// func polymulAsm(acc, key *Element)
+TEXT ·polymulAsm(SB), NOSPLIT, $0-16
+#define acc_ptr X28
+#define key_ptr X29
+
+	MOV acc+0(FP), acc_ptr
+	MOV key+8(FP), key_ptr
+	MOV $0xc200000000000000, poly
+
+	LOAD_ALIGNED(acc_ptr, 0)
+	LOAD_KEY(key_ptr, 0)
+
+	MUL()
+	REDUCE()
+
+	MOV dlo, 0(acc_ptr)
+	MOV dhi, 8(acc_ptr)
+	RET
+
+#undef acc_ptr
+#undef key_ptr
+
+// func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+TEXT ·polymulBlocksAsm(SB), NOSPLIT, $0-32
+#define acc_ptr X28
+#define pow_ptr X29
+#define input_ptr X30
+#define remain X12
+#define single X13
+
+	MOV acc+0(FP), acc_ptr
+	MOV pow+8(FP), pow_ptr
+	MOV input+16(FP), input_ptr
+	MOV nblocks+24(FP), remain
+	MOV $0xc200000000000000, poly
+
+	MOV 0(acc_ptr), dlo
+	MOV 8(acc_ptr), dhi
+
+	// Handle nblocks%8 blocks one at a time using pow[15].
+	AND  $7, remain, single
+	SRL  $3, remain
+	LOAD_KEY(pow_ptr, 15*16)
+
+	AND  $7, input_ptr, X14
+	BNEZ X14, unaligned
+
+	BEQZ single, wideLoop
+
+singleLoop:
+	SINGLE(LOAD_ALIGNED)
+
+	ADD  $16, input_ptr
+	ADD  $-1, single
+	BNEZ single, singleLoop
+
+	BEQZ remain, done
+
+wideLoop:
+	WIDE(LOAD_ALIGNED)
+
+	ADD  $(8*16), input_ptr
+	ADD  $-1, remain
+	BNEZ remain, wideLoop
+	JMP  done
+
+unaligned:
+	BEQZ single, wideLoopUnaligned
+
+singleLoopUnaligned:
+	SINGLE(LOAD_UNALIGNED)
+
+	ADD  $16, input_ptr
+	ADD  $-1, single
+	BNEZ single, singleLoopUnaligned
+
+	BEQZ remain, done
+
+wideLoopUnaligned:
+	WIDE(LOAD_UNALIGNED)
+
+	ADD  $(8*16), input_ptr
+	ADD  $-1, remain
+	BNEZ remain, wideLoopUnaligned
+
+done:
+	MOV dlo, 0(acc_ptr)
+	MOV dhi, 8(acc_ptr)
+	RET
+
+#undef acc_ptr
+#undef pow_ptr
+#undef input_ptr
+#undef remain
+#undef single
+
+// func polymulBlocksAsmZvbc(acc *Element, pow *[16]Element, input *byte, nblocks int)
+TEXT ·polymulBlocksAsmZvbc(SB), NOSPLIT, $0-32
+#define acc_ptr X28
+#define pow_ptr X29
+#define input_ptr X30
+#define remain X12
+#define single X13
+
+	// Unaligned inputs and inputs with fewer than eight
+	// blocks are left to polymulBlocksAsm.
+	MOV  input+16(FP), input_ptr
+	MOV  nblocks+24(FP), remain
+	AND  $7, input_ptr, X14
+	BNEZ X14, scalar
+	SRL  $3, remain, X14
+	BEQZ X14, scalar
+
+	MOV acc+0(FP), acc_ptr
+	MOV pow+8(FP), pow_ptr
+	MOV $0xc200000000000000, poly
+
+	MOV 0(acc_ptr), dlo
+	MOV 8(acc_ptr), dhi
+
+	// Load pow[8:16] into v8 and v12 and zero element 0 of v0
+	// for VREDXOR.
+	VSETIVLI_8_E64_M4
+	ADD $(8*16), pow_ptr, X14
+	VLSEG2E64(8, 14)
+	VMV_S_X(0, 0)
+
+	// Handle nblocks%8 blocks one at a time using pow[15].
+	AND  $7, remain, single
+	SRL  $3, remain
+	LOAD_KEY(pow_ptr, 15*16)
+
+	BEQZ single, wideLoop
+
+singleLoop:
+	SINGLE(LOAD_ALIGNED)
+
+	ADD  $16, input_ptr
+	ADD  $-1, single
+	BNEZ single, singleLoop
+
+wideLoop:
+	WIDE_ZVBC()
+
+	ADD  $(8*16), input_ptr
+	ADD  $-1, remain
+	BNEZ remain, wideLoop
+
+	MOV dlo, 0(acc_ptr)
+	MOV dhi, 8(acc_ptr)
+	RET
+
+scalar:
+	JMP ·polymulBlocksAsm(SB)
+
+#undef acc_ptr
+#undef pow_ptr
+#undef input_ptr
+#undef remain
+#undef single
+
+// func ctmulAsm(x, y uint64) (z1, z0 uint64)
+TEXT ·ctmulAsm(SB), NOSPLIT, $0-32
+	MOV x+0(FP), X5
+	MOV y+8(FP), X7
+	CLMULH(9, 5, 7)
+	CLMUL(10, 5, 7)
+	MOV X9, z1+16(FP)
+	MOV X10, z0+24(FP)
+	RET
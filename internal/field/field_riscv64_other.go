@@ -0,0 +1,18 @@
+//go:build riscv64 && !linux && gc && !purego && !noasm && !tinygo
+
+package field
+
+// hasZbc reports whether every CPU supports the Zbc extension.
+//
+// Only Linux provides a way to detect it.
+func hasZbc() bool {
+	return false
+}
+
+// hasZvbc reports whether every CPU supports the V and Zvbc
+// extensions.
+//
+// Only Linux provides a way to detect them.
+func hasZvbc() bool {
+	return false
+}
@@ -0,0 +1,233 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+#include "textflag.h"
+
+// The following assembly uses the vector facility's Galois
+// field multiply-sum instructions. See MulGeneric and
+// MulBlocksGeneric for more information on the algorithm.
+//
+// s390x is big endian, so inside the assembly a field element
+// is kept as a 128-bit integer: doubleword 0 is the high half
+// and doubleword 1 is the low half. Loading an Element from
+// memory yields {lo, hi}, which is swapped with VPDI, and
+// loading an input block yields its byte reversal, which is
+// fixed with VPERM.
+//
+// VGFMG computes x0*y0 ⊕ x1*y1, so instead of Karatsuba we
+// multiply x by three variants of the key:
+//
+//    hi  = x*{y.hi, 0}    = x.hi*y.hi
+//    lo  = x*{0, y.lo}    = x.lo*y.lo
+//    mid = x*{y.lo, y.hi} = x.hi*y.lo ⊕ x.lo*y.hi
+//
+// Conveniently, {y.lo, y.hi} is the key as loaded from memory.
+
+DATA consts<>+0(SB)/8, $0x0f0e0d0c0b0a0908  // byte reversal
+DATA consts<>+8(SB)/8, $0x0706050403020100
+DATA consts<>+16(SB)/8, $0x0000000000000000 // {0, poly}
+DATA consts<>+24(SB)/8, $0xc200000000000000
+GLOBL consts<>(SB), RODATA|NOPTR, $32
+
+#define d V0
+#define H V1
+#define L V2
+#define M V3
+#define X V4
+#define poly V5
+#define zero V6
+#define rev V7
+
+// LOAD_KEY loads the key at |addr| and writes the three
+// variants to |khi|, |klo|, and |kmid|.
+#define LOAD_KEY(addr, khi, klo, kmid) \
+	VL   addr, kmid                \
+	VPDI $4, kmid, zero, khi       \
+	VPDI $0, zero, kmid, klo
+
+// MUL computes the 256-bit product of |X| and the key.
+//
+// The results are written directly to |H|, |L|, and |M|.
+#define MUL(khi, klo, kmid) \
+	VGFMG khi, X, H     \
+	VGFMG klo, X, L     \
+	VGFMG kmid, X, M
+
+// MUL_XOR computes the 256-bit product of |X| and the key.
+//
+// The results are XORed with |H|, |L|, and |M|.
+#define MUL_XOR(khi, klo, kmid) \
+	VGFMAG khi, X, H, H     \
+	VGFMAG klo, X, L, L     \
+	VGFMAG kmid, X, M, M
+
+// REDUCE performs Montgomery reduction on |H|, |L|, and |M|.
+//
+// The result is written to |d|.
+//
+// First, the middle term is added to the high and low halves:
+//
+//    L = {l1^m0, l0} = {x1, x0}
+//    H = {h1, h0^m1} = {x3, x2}
+//
+// Then, perform the Montgomery reduction over the 256-bit X.
+//    [A1:A0] = X0 • 0xc200000000000000
+//    [B1:B0] = [X0 ⊕ A1 : X1 ⊕ A0]
+//    [C1:C0] = B0 • 0xc200000000000000
+//    [D1:D0] = [B0 ⊕ C1 : B1 ⊕ C0]
+// Output: [D1 ⊕ X3 : D0 ⊕ X2]
+//
+// VGFMAG computes B in a single instruction since the high
+// half of |poly| is zero.
+#define REDUCE() \
+	VPDI   $4, M, zero, X \
+	VX     X, L, L        \
+	VPDI   $0, zero, M, X \
+	VX     X, H, H        \
+	VPDI   $4, L, L, X    \
+	VGFMAG poly, L, X, M  \
+	VGFMAG poly, M, H, H  \
+	VPDI   $4, M, M, X    \
+	VX     X, H, d
+
+// func polymulAsm(acc, key *Element)
+TEXT ·polymulAsm(SB), NOSPLIT, $0-16
+#define acc_ptr R1
+#define key_ptr R2
+
+	MOVD acc+0(FP), acc_ptr
+	MOVD key+8(FP), key_ptr
+	MOVD $consts<>(SB), R3
+	VL   16(R3), poly
+	VZERO zero
+
+	VL   (acc_ptr), X
+	VPDI $4, X, X, X
+	LOAD_KEY((key_ptr), V8, V9, V10)
+
+	MUL(V8, V9, V10)
+	REDUCE()
+
+	VPDI $4, d, d, d
+	VST  d, (acc_ptr)
+	RET
+
+#undef acc_ptr
+#undef key_ptr
+
+// func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+TEXT ·polymulBlocksAsm(SB), NOSPLIT, $0-32
+#define acc_ptr R1
+#define pow_ptr R2
+#define input_ptr R3
+#define remain R4
+#define single R5
+
+	MOVD acc+0(FP), acc_ptr
+	MOVD pow+8(FP), pow_ptr
+	MOVD input+16(FP), input_ptr
+	MOVD nblocks+24(FP), remain
+	MOVD $consts<>(SB), R6
+	VL   0(R6), rev
+	VL   16(R6), poly
+	VZERO zero
+
+	// Load pow[8:16] into V8-V31.
+	LOAD_KEY(8*16(pow_ptr), V8, V9, V10)
+	LOAD_KEY(9*16(pow_ptr), V11, V12, V13)
+	LOAD_KEY(10*16(pow_ptr), V14, V15, V16)
+	LOAD_KEY(11*16(pow_ptr), V17, V18, V19)
+	LOAD_KEY(12*16(pow_ptr), V20, V21, V22)
+	LOAD_KEY(13*16(pow_ptr), V23, V24, V25)
+	LOAD_KEY(14*16(pow_ptr), V26, V27, V28)
+	LOAD_KEY(15*16(pow_ptr), V29, V30, V31)
+
+	VL   (acc_ptr), d
+	VPDI $4, d, d, d
+
+	// Handle nblocks%8 blocks one at a time using pow[15].
+	MOVD   remain, single
+	AND    $7, single
+	CMPBEQ single, $0, initWideLoop
+
+singleLoop:
+	VL    (input_ptr), X
+	VPERM X, X, rev, X
+	VX    d, X, X
+	MUL(V29, V30, V31)
+	REDUCE()
+
+	ADD   $16, input_ptr
+	BRCTG single, singleLoop
+
+initWideLoop:
+	SRD    $3, remain
+	CMPBEQ remain, $0, done
+
+wideLoop:
+	// Block 0
+	VL    0*16(input_ptr), X
+	VPERM X, X, rev, X
+	VX    d, X, X
+	MUL(V8, V9, V10)
+
+	// Block 1
+	VL    1*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V11, V12, V13)
+
+	// Block 2
+	VL    2*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V14, V15, V16)
+
+	// Block 3
+	VL    3*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V17, V18, V19)
+
+	// Block 4
+	VL    4*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V20, V21, V22)
+
+	// Block 5
+	VL    5*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V23, V24, V25)
+
+	// Block 6
+	VL    6*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V26, V27, V28)
+
+	// Block 7
+	VL    7*16(input_ptr), X
+	VPERM X, X, rev, X
+	MUL_XOR(V29, V30, V31)
+
+	REDUCE()
+
+	ADD   $(8*16), input_ptr
+	BRCTG remain, wideLoop
+
+done:
+	VPDI $4, d, d, d
+	VST  d, (acc_ptr)
+	RET
+
+#undef acc_ptr
+#undef pow_ptr
+#undef input_ptr
+#undef remain
+#undef single
+
+// func ctmulAsm(x, y uint64) (z1, z0 uint64)
+TEXT ·ctmulAsm(SB), NOSPLIT, $0-32
+	VZERO V1
+	VZERO V2
+	VLEG  $1, x+0(FP), V1
+	VLEG  $1, y+8(FP), V2
+	VGFMG V1, V2, V3
+	VSTEG $0, V3, z1+16(FP)
+	VSTEG $1, V3, z0+24(FP)
+	RET
@@ -0,0 +1,264 @@
+package field
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/testutil"
+	"golang.org/x/exp/rand"
+)
+
+// TestCtmul tests that ctmul is commutative, a required
+// property for multiplication, and that it matches
+// ctmulGeneric.
+func TestCtmul(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1e6; i++ {
+		x, y := rng.Uint64(), rng.Uint64()
+		xy1, xy0 := ctmul(x, y)
+		yx1, yx0 := ctmul(y, x)
+		if xy1 != yx1 || xy0 != yx0 {
+			t.Fatalf("%#0.16x*%#0.16x: (%#0.16x, %#0.16x) != (%#0.16x, %#0.16x)",
+				x, y, xy1, xy0, yx1, yx0)
+		}
+		g1, g0 := ctmulGeneric(x, y)
+		if xy1 != g1 || xy0 != g0 {
+			t.Fatalf("%#0.16x*%#0.16x: (%#0.16x, %#0.16x) != (%#0.16x, %#0.16x)",
+				x, y, xy1, xy0, g1, g0)
+		}
+	}
+}
+
+// TestCtmulGeneric32 tests that ctmulGeneric32 matches
+// ctmulGeneric64.
+func TestCtmulGeneric32(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1e6; i++ {
+		x, y := rng.Uint64(), rng.Uint64()
+		want1, want0 := ctmulGeneric64(x, y)
+		got1, got0 := ctmulGeneric32(x, y)
+		if got1 != want1 || got0 != want0 {
+			t.Fatalf("%#0.16x*%#0.16x: (%#0.16x, %#0.16x) != (%#0.16x, %#0.16x)",
+				x, y, got1, got0, want1, want0)
+		}
+	}
+}
+
+// TestMulBlocksGeneric32 tests that mulBlocksGeneric32
+// matches mulBlocksGeneric64.
+func TestMulBlocksGeneric32(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1e3; i++ {
+		var pow [8]Element
+		for j := range pow {
+			pow[j] = Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		}
+		blocks := make([]byte, 16*rng.Intn(64))
+		rng.Read(blocks)
+		acc := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+
+		want := acc
+		mulBlocksGeneric64(&want, &pow, blocks)
+		got := acc
+		mulBlocksGeneric32(&got, &pow, blocks)
+		if got != want {
+			t.Fatalf("#%d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+// TestMulBlocks tests that MulBlocks matches MulBlocksGeneric
+// for every remainder of the wide stride.
+func TestMulBlocks(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 100; i++ {
+		var pow [16]Element
+		pow[15] = Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		for j := len(pow) - 2; j >= 0; j-- {
+			pow[j] = pow[15]
+			MulGeneric(&pow[j], &pow[j+1])
+		}
+		for n := 0; n <= 48; n++ {
+			blocks := make([]byte, 16*n)
+			rng.Read(blocks)
+			acc := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+
+			want := acc
+			MulBlocksGeneric(&want, &pow, blocks)
+			got := acc
+			MulBlocks(&got, &pow, blocks)
+			if got != want {
+				t.Fatalf("#%d: %d blocks: expected %v, got %v",
+					i, n, want, got)
+			}
+		}
+	}
+}
+
+// TestReverseBlocks tests that ReverseBlocks reverses each
+// block, both in place and into another slice.
+func TestReverseBlocks(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for n := 0; n <= 21; n++ {
+		src := make([]byte, 16*n)
+		rng.Read(src)
+		want := make([]byte, len(src))
+		for i := range src {
+			want[i] = src[i^15]
+		}
+
+		got := make([]byte, len(src))
+		ReverseBlocks(got, src)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%d blocks: expected %x, got %x", n, want, got)
+		}
+		ReverseBlocks(src, src)
+		if !bytes.Equal(src, want) {
+			t.Fatalf("%d blocks in place: expected %x, got %x", n, want, src)
+		}
+	}
+}
+
+// TestKernels tests that every kernel supported by the CPU
+// matches the generic implementation.
+func TestKernels(t *testing.T) {
+	defer selectKernel(Kernel)
+
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for _, k := range supportedKernels {
+		selectKernel(k.name)
+		if Kernel != k.name {
+			t.Fatalf("expected kernel %q, got %q", k.name, Kernel)
+		}
+		for i := 0; i < 100; i++ {
+			var pow [16]Element
+			pow[15] = Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+			for j := len(pow) - 2; j >= 0; j-- {
+				pow[j] = pow[15]
+				MulGeneric(&pow[j], &pow[j+1])
+			}
+			blocks := make([]byte, 16*rng.Intn(64))
+			rng.Read(blocks)
+			acc := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+
+			want := acc
+			MulBlocksGeneric(&want, &pow, blocks)
+			got := acc
+			MulBlocks(&got, &pow, blocks)
+			if got != want {
+				t.Fatalf("%s: #%d: expected %v, got %v",
+					k.name, i, want, got)
+			}
+
+			want = acc
+			MulGeneric(&want, &pow[15])
+			got = acc
+			Mul(&got, &pow[15])
+			if got != want {
+				t.Fatalf("%s: #%d: expected %v, got %v",
+					k.name, i, want, got)
+			}
+		}
+	}
+}
+
+// TestSelectKernel tests that "auto" and unknown names select
+// a supported kernel.
+func TestSelectKernel(t *testing.T) {
+	defer selectKernel(Kernel)
+
+	ks := supportedKernels
+	for _, env := range []string{"auto", "bogus"} {
+		selectKernel(env)
+		ok := false
+		for _, k := range ks {
+			ok = ok || k.name == Kernel
+		}
+		if !ok {
+			t.Fatalf("%q: selected unsupported kernel %q", env, Kernel)
+		}
+	}
+	selectKernel("bogus")
+	if Kernel != ks[0].name {
+		t.Fatalf("expected %q, got %q", ks[0].name, Kernel)
+	}
+}
+
+// TestUseKernel tests that UseKernel selects each kernel listed
+// by Kernels and rejects unknown names.
+func TestUseKernel(t *testing.T) {
+	defer selectKernel(Kernel)
+
+	for _, name := range Kernels() {
+		if !UseKernel(name) {
+			t.Fatalf("unable to select %q", name)
+		}
+		if Kernel != name {
+			t.Fatalf("expected kernel %q, got %q", name, Kernel)
+		}
+	}
+	old := Kernel
+	if UseKernel("bogus") {
+		t.Fatal("selected an unknown kernel")
+	}
+	if Kernel != old {
+		t.Fatalf("expected kernel %q, got %q", old, Kernel)
+	}
+}
+
+// TestSquare tests that Square(x) = Mul(x, x) for both the
+// generic and specialized implementations.
+func TestSquare(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1e5; i++ {
+		x := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+
+		want := x
+		MulGeneric(&want, &x)
+
+		got := x
+		SquareGeneric(&got)
+		if got != want {
+			t.Fatalf("%v^2: expected %v, got %v", x, want, got)
+		}
+
+		got = x
+		Square(&got)
+		if got != want {
+			t.Fatalf("%v^2: expected %v, got %v", x, want, got)
+		}
+
+		got = x
+		Mul(&got, &x)
+		if got != want {
+			t.Fatalf("%v*%v: expected %v, got %v", x, x, want, got)
+		}
+	}
+}
+
+func TestInlining(t *testing.T) {
+	want := []string{
+		"Element.String",
+		"(*Element).SetBytes",
+	}
+	testutil.TestInlining(t, "github.com/ericlagergren/polyval/internal/field", want...)
+}
+
+var ctmulSink uint64
+
+func BenchmarkCtmul(b *testing.B) {
+	z1 := rand.Uint64()
+	z0 := rand.Uint64()
+	for i := 0; i < b.N; i++ {
+		z1, z0 = ctmul(z1, z0)
+	}
+	ctmulSink = z1 ^ z0
+}
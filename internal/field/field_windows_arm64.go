@@ -0,0 +1,36 @@
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// x/sys/cpu does not detect arm64 features on Windows, so ask
+// the kernel directly.
+//
+// See https://learn.microsoft.com/en-us/windows/win32/api/processthreadsapi/nf-processthreadsapi-isprocessorfeaturepresent
+var procIsProcessorFeaturePresent = windows.NewLazySystemDLL("kernel32.dll").
+	NewProc("IsProcessorFeaturePresent")
+
+// pfARMV8CryptoInstructionsAvailable covers AES, PMULL,
+// SHA-1, and SHA-256.
+const pfARMV8CryptoInstructionsAvailable = 30
+
+// hasPMULL reports whether the CPU supports PMULL.
+func hasPMULL() bool {
+	if procIsProcessorFeaturePresent.Find() != nil {
+		return false
+	}
+	r, _, _ := procIsProcessorFeaturePresent.Call(pfARMV8CryptoInstructionsAvailable)
+	return r != 0
+}
+
+// hasSHA3 reports whether the CPU supports the SHA-3
+// extensions.
+//
+// Most Windows releases cannot report SHA-3 support, so it is
+// assumed to be missing.
+func hasSHA3() bool {
+	return false
+}
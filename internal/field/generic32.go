@@ -0,0 +1,123 @@
+package field
+
+import "encoding/binary"
+
+// ctmulGeneric32 returns the constant time 128-bit product of
+// x and y in GF(2^128).
+//
+// It is used on platforms without a fast 64x64->128 bit
+// multiply, where each bits.Mul64 in ctmulGeneric64 takes four
+// multiplications plus carry propagation. Instead, it uses
+// one level of Karatsuba over 32-bit halves, computing each
+// half with bmul32.
+func ctmulGeneric32(x, y uint64) (z1, z0 uint64) {
+	x0, x1 := uint32(x), uint32(x>>32)
+	y0, y1 := uint32(y), uint32(y>>32)
+
+	lo := bmul32(x0, y0)
+	hi := bmul32(x1, y1)
+	mid := bmul32(x0^x1, y0^y1) ^ lo ^ hi
+
+	z0 = lo ^ mid<<32
+	z1 = hi ^ mid>>32
+	return
+}
+
+// bmul32 returns the constant time 64-bit carry-less product
+// of x and y.
+//
+// Both x and y are split into 4 words with three-bit holes.
+// Each word has at most 8 bits set, so each coefficient of
+// the product of two words is at most 8 and never carries
+// into the next coefficient of the same word.
+//
+// See https://www.bearssl.org/constanttime.html
+func bmul32(x, y uint32) uint64 {
+	x0 := uint64(x & 0x11111111)
+	x1 := uint64(x & 0x22222222)
+	x2 := uint64(x & 0x44444444)
+	x3 := uint64(x & 0x88888888)
+	y0 := uint64(y & 0x11111111)
+	y1 := uint64(y & 0x22222222)
+	y2 := uint64(y & 0x44444444)
+	y3 := uint64(y & 0x88888888)
+
+	z0 := (x0 * y0) ^ (x1 * y3) ^ (x2 * y2) ^ (x3 * y1)
+	z1 := (x0 * y1) ^ (x1 * y0) ^ (x2 * y3) ^ (x3 * y2)
+	z2 := (x0 * y2) ^ (x1 * y1) ^ (x2 * y0) ^ (x3 * y3)
+	z3 := (x0 * y3) ^ (x1 * y2) ^ (x2 * y1) ^ (x3 * y0)
+
+	return z0&0x1111111111111111 |
+		z1&0x2222222222222222 |
+		z2&0x4444444444444444 |
+		z3&0x8888888888888888
+}
+
+// mulBlocksGeneric32 is mulBlocksGeneric64 built on bmul32
+// instead of ctmulGeneric.
+//
+// The 128-bit multiplication is two levels of Karatsuba, so
+// each block needs nine calls to bmul32. Karatsuba is linear,
+// so the nine products are summed over all eight blocks and
+// only recombined once before the reduction.
+func mulBlocksGeneric32(acc *Element, pow *[8]Element, blocks []byte) {
+	for (len(blocks)/16)%8 != 0 {
+		acc.Lo ^= binary.LittleEndian.Uint64(blocks[0:8])
+		acc.Hi ^= binary.LittleEndian.Uint64(blocks[8:16])
+		MulGeneric(acc, &pow[len(pow)-1])
+		blocks = blocks[16:]
+	}
+
+	const (
+		wide = 16 * len(pow)
+	)
+	for len(blocks) >= wide {
+		// The low, high, and middle products of H, L, and M.
+		var hl, hh, hm, ll, lh, lm, ml, mh, mm uint64
+		for i, x := range pow {
+			var y Element
+			y.SetBytes(blocks[:16])
+			if i == 0 {
+				y.Lo ^= acc.Lo
+				y.Hi ^= acc.Hi
+			}
+
+			x0, x1 := uint32(x.Lo), uint32(x.Lo>>32)
+			x2, x3 := uint32(x.Hi), uint32(x.Hi>>32)
+			y0, y1 := uint32(y.Lo), uint32(y.Lo>>32)
+			y2, y3 := uint32(y.Hi), uint32(y.Hi>>32)
+
+			hl ^= bmul32(x2, y2)
+			hh ^= bmul32(x3, y3)
+			hm ^= bmul32(x2^x3, y2^y3)
+
+			ll ^= bmul32(x0, y0)
+			lh ^= bmul32(x1, y1)
+			lm ^= bmul32(x0^x1, y0^y1)
+
+			ml ^= bmul32(x0^x2, y0^y2)
+			mh ^= bmul32(x1^x3, y1^y3)
+			mm ^= bmul32(x0^x1^x2^x3, y0^y1^y2^y3)
+
+			blocks = blocks[16:]
+		}
+
+		hm ^= hl ^ hh
+		h1, h0 := hh^hm>>32, hl^hm<<32
+		lm ^= ll ^ lh
+		l1, l0 := lh^lm>>32, ll^lm<<32
+		mm ^= ml ^ mh
+		m1, m0 := mh^mm>>32, ml^mm<<32
+
+		m0 ^= l0 ^ h0
+		m1 ^= l1 ^ h1
+
+		l1 ^= m0 ^ (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
+		h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
+		h0 ^= m1 ^ (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
+		h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
+
+		acc.Hi = h1
+		acc.Lo = h0
+	}
+}
@@ -0,0 +1,16 @@
+//go:build 386 || arm || mips || mipsle || wasm
+
+package field
+
+// These platforms do not have a fast 64x64->128 bit multiply,
+// so the generic code is built on 32x32->64 bit multiplies.
+
+// ctmulGeneric returns the constant time 128-bit product of
+// x and y in GF(2^128).
+func ctmulGeneric(x, y uint64) (z1, z0 uint64) {
+	return ctmulGeneric32(x, y)
+}
+
+func mulBlocksGeneric(acc *Element, pow *[8]Element, blocks []byte) {
+	mulBlocksGeneric32(acc, pow, blocks)
+}
@@ -0,0 +1,13 @@
+//go:build !(386 || arm || mips || mipsle || wasm)
+
+package field
+
+// ctmulGeneric returns the constant time 128-bit product of
+// x and y in GF(2^128).
+func ctmulGeneric(x, y uint64) (z1, z0 uint64) {
+	return ctmulGeneric64(x, y)
+}
+
+func mulBlocksGeneric(acc *Element, pow *[8]Element, blocks []byte) {
+	mulBlocksGeneric64(acc, pow, blocks)
+}
@@ -0,0 +1,225 @@
+package field
+
+import (
+	"encoding/binary"
+	"os"
+	"time"
+)
+
+// kernel is a set of multiplication kernels that can be
+// selected at runtime.
+//
+// Each architecture's kernels function returns the kernels
+// supported by the CPU, most preferred first, ending with the
+// generic kernel.
+type kernel struct {
+	name string
+	// use selects the kernel.
+	use func()
+}
+
+var (
+	// supportedKernels is the result of kernels before any
+	// kernel is selected.
+	supportedKernels = kernels()
+	// Kernel is the name of the kernel selected at init.
+	Kernel string
+	// FailedKernels lists the supported kernels that failed
+	// their known-answer test at init and are never selected.
+	FailedKernels []string
+)
+
+func init() {
+	initKernel()
+}
+
+// initKernel selects the kernel at init.
+//
+// Selecting a kernel tests and possibly times the kernels
+// through MulBlocks, so the counters reported by ReadStats are
+// reset afterward to only count the caller's work.
+func initKernel() {
+	selectKernel(os.Getenv("POLYVAL_KERNEL"))
+	resetStats()
+}
+
+// selectKernel selects a kernel according to env, the value of
+// the POLYVAL_KERNEL environment variable.
+//
+// If env names a kernel supported by the CPU, that kernel is
+// used. If env is "auto", each supported kernel is timed on
+// a short input and the fastest is used. Otherwise, the kernel
+// chosen by CPU feature detection is used.
+//
+// Kernels that fail testKernel are skipped. Emulators and
+// hypervisors occasionally misexecute carry-less multiplies,
+// and a wrong hash is worse than a slow one.
+func selectKernel(env string) {
+	// The generic kernel is the reference the others are
+	// tested against, so it is always available. Skipping its
+	// test also keeps init cheap on targets that only have the
+	// generic kernel, like TinyGo.
+	n := len(supportedKernels) - 1
+	var ks []kernel
+	FailedKernels = nil
+	for _, k := range supportedKernels[:n] {
+		if testKernel(k) {
+			ks = append(ks, k)
+		} else {
+			FailedKernels = append(FailedKernels, k.name)
+		}
+	}
+	ks = append(ks, supportedKernels[n])
+
+	k := ks[0]
+	switch env {
+	case "", k.name:
+	case "auto":
+		k = fastestKernel(ks)
+	default:
+		for _, v := range ks {
+			if v.name == env {
+				k = v
+				break
+			}
+		}
+	}
+	k.use()
+	Kernel = k.name
+}
+
+// Kernels returns the names of the kernels that can be
+// selected, most preferred first.
+//
+// It omits the kernels in FailedKernels.
+func Kernels() []string {
+	var names []string
+	for _, k := range supportedKernels {
+		if !failed(k.name) {
+			names = append(names, k.name)
+		}
+	}
+	return names
+}
+
+// UseKernel selects the kernel named name and reports whether
+// it exists.
+//
+// It is meant for tests. It is not safe to call concurrently
+// with any other function in this package.
+func UseKernel(name string) bool {
+	for _, k := range supportedKernels {
+		if k.name == name && !failed(name) {
+			k.use()
+			Kernel = k.name
+			return true
+		}
+	}
+	return false
+}
+
+// failed reports whether name is in FailedKernels.
+func failed(name string) bool {
+	for _, v := range FailedKernels {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// katKey, katMsg, and katSum are the POLYVAL(H, X_1, X_2) test
+// vector from RFC 8452 appendix A.
+var (
+	katKey = Element{Lo: 0x7642925847936225, Hi: 0x7b754bba26f8311d}
+	katMsg = [2]Element{
+		{Lo: 0xb6df838c66954f4f, Hi: 0x62a2012dbb621740},
+		{Lo: 0x06d02127dd4da2d1, Hi: 0x62f3c9d3205fe4bb},
+	}
+	katSum = Element{Lo: 0xfa1961847bb4a3f7, Hi: 0x7eb7e5f56c86b7e5}
+)
+
+// testKernel reports whether k computes the RFC 8452 test vector
+// and agrees with the generic kernel on an input that exercises
+// every path through MulBlocks.
+//
+// The generic kernel never uses the carry-less multiply
+// instructions, so a kernel that misexecutes them disagrees
+// with it.
+func testKernel(k kernel) bool {
+	k.use()
+
+	var pow, want [16]Element
+	pow[15], want[15] = katKey, katKey
+	for i := len(pow) - 2; i >= 0; i-- {
+		pow[i] = katKey
+		Mul(&pow[i], &pow[i+1])
+		want[i] = katKey
+		MulGeneric(&want[i], &want[i+1])
+	}
+	if pow != want {
+		return false
+	}
+
+	var msg [2 * 16]byte
+	for i, x := range katMsg {
+		binary.LittleEndian.PutUint64(msg[i*16:], x.Lo)
+		binary.LittleEndian.PutUint64(msg[i*16+8:], x.Hi)
+	}
+	var acc Element
+	MulBlocks(&acc, &pow, msg[:])
+	if acc != katSum {
+		return false
+	}
+
+	// One wide stride plus a 3-, 4-, and 8-block remainder.
+	var blocks [16 * (16 + 8 + 4 + 3)]byte
+	for i := range blocks {
+		blocks[i] = byte(i)
+	}
+	got := katSum
+	MulBlocks(&got, &pow, blocks[:])
+	exp := katSum
+	MulBlocksGeneric(&exp, &pow, blocks[:])
+	return got == exp
+}
+
+// calibrationSize is the size in bytes of the input used to
+// time each kernel.
+//
+// It is long enough to exercise the wide loops but short
+// enough to keep the cost at init negligible.
+const calibrationSize = 1024
+
+// fastestKernel returns the kernel in ks that takes the least
+// time to write a calibrationSize input.
+func fastestKernel(ks []kernel) kernel {
+	var pow [16]Element
+	for i := range pow {
+		pow[i] = Element{Lo: uint64(i) + 1, Hi: uint64(i)<<32 | 1}
+	}
+	blocks := make([]byte, calibrationSize)
+
+	var best kernel
+	var bestTime time.Duration
+	for _, k := range ks {
+		k.use()
+		var acc Element
+		// Take the minimum of several runs to filter out
+		// preemption and cache misses.
+		t := time.Duration(1<<63 - 1)
+		for i := 0; i < 5; i++ {
+			start := time.Now()
+			for j := 0; j < 16; j++ {
+				MulBlocks(&acc, &pow, blocks)
+			}
+			if d := time.Since(start); d < t {
+				t = d
+			}
+		}
+		if best.use == nil || t < bestTime {
+			best, bestTime = k, t
+		}
+	}
+	return best
+}
@@ -0,0 +1,158 @@
+package field
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// This file implements GF(2^128) one bit at a time with
+// math/big as an oracle for the field arithmetic. It shares no
+// code or tricks with the implementations under test: no
+// Karatsuba, no Montgomery reduction, and no precomputed
+// constants other than the polynomial itself.
+
+// refPoly is x^128 + x^127 + x^126 + x^121 + 1.
+var refPoly = func() *big.Int {
+	p := new(big.Int)
+	for _, i := range []int{128, 127, 126, 121, 0} {
+		p.SetBit(p, i, 1)
+	}
+	return p
+}()
+
+// refXInv128 is x^-128 mod refPoly.
+var refXInv128 = func() *big.Int {
+	// x^-1 = x^(2^128-2), since the multiplicative group has
+	// order 2^128-1.
+	x := big.NewInt(2)
+	inv := big.NewInt(1)
+	for i := 1; i < 128; i++ {
+		inv = refMul(refMul(inv, inv), x)
+	}
+	inv = refMul(inv, inv)
+	z := big.NewInt(1)
+	for i := 0; i < 128; i++ {
+		z = refMul(z, inv)
+	}
+	return z
+}()
+
+// refMul returns x*y mod refPoly.
+func refMul(x, y *big.Int) *big.Int {
+	z := new(big.Int)
+	t := new(big.Int)
+	for i := 0; i < y.BitLen(); i++ {
+		if y.Bit(i) == 1 {
+			z.Xor(z, t.Lsh(x, uint(i)))
+		}
+	}
+	for z.BitLen() > 128 {
+		z.Xor(z, t.Lsh(refPoly, uint(z.BitLen()-129)))
+	}
+	return z
+}
+
+// refDot returns x*y*x^-128 mod refPoly, which is what
+// MulGeneric computes.
+func refDot(x, y *big.Int) *big.Int {
+	return refMul(refMul(x, y), refXInv128)
+}
+
+func toBig(x Element) *big.Int {
+	z := new(big.Int).SetUint64(x.Hi)
+	z.Lsh(z, 64)
+	return z.Or(z, new(big.Int).SetUint64(x.Lo))
+}
+
+func fromBig(x *big.Int) Element {
+	lo := new(big.Int).And(x, new(big.Int).SetUint64(^uint64(0)))
+	hi := new(big.Int).Rsh(x, 64)
+	return Element{Lo: lo.Uint64(), Hi: hi.Uint64()}
+}
+
+// TestRefXInv128 tests the oracle's constant.
+func TestRefXInv128(t *testing.T) {
+	x128 := new(big.Int).Lsh(big.NewInt(1), 128)
+	if z := refMul(refXInv128, x128); z.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("x^128 * x^-128 = %x", z)
+	}
+}
+
+// TestMulReference tests multiplication and squaring against the
+// bit-level oracle.
+func TestMulReference(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 1e4; i++ {
+		x := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		y := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		want := fromBig(refDot(toBig(x), toBig(y)))
+
+		got := x
+		MulGeneric(&got, &y)
+		if got != want {
+			t.Fatalf("MulGeneric(%v, %v): expected %v, got %v", x, y, want, got)
+		}
+		got = x
+		Mul(&got, &y)
+		if got != want {
+			t.Fatalf("Mul(%v, %v): expected %v, got %v", x, y, want, got)
+		}
+
+		want = fromBig(refDot(toBig(x), toBig(x)))
+		got = x
+		SquareGeneric(&got)
+		if got != want {
+			t.Fatalf("SquareGeneric(%v): expected %v, got %v", x, want, got)
+		}
+		got = x
+		Square(&got)
+		if got != want {
+			t.Fatalf("Square(%v): expected %v, got %v", x, want, got)
+		}
+	}
+}
+
+// TestMulBlocksReference tests MulBlocks and MulBlocksGeneric
+// against Horner's rule evaluated with the bit-level oracle.
+func TestMulBlocksReference(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < 20; i++ {
+		h := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		var pow [16]Element
+		pow[15] = fromBig(toBig(h))
+		for j := len(pow) - 2; j >= 0; j-- {
+			pow[j] = fromBig(refDot(toBig(pow[j+1]), toBig(h)))
+		}
+		for _, n := range []int{0, 1, 7, 8, 9, 15, 16, 17, 33, 64} {
+			blocks := make([]byte, 16*n)
+			rng.Read(blocks)
+			acc := Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+
+			y := toBig(acc)
+			for j := 0; j < len(blocks); j += 16 {
+				var x Element
+				x.SetBytes(blocks[j : j+16])
+				y = refDot(y.Xor(y, toBig(x)), toBig(h))
+			}
+			want := fromBig(y)
+
+			got := acc
+			MulBlocksGeneric(&got, &pow, blocks)
+			if got != want {
+				t.Fatalf("MulBlocksGeneric: %d blocks: expected %v, got %v",
+					n, want, got)
+			}
+			got = acc
+			MulBlocks(&got, &pow, blocks)
+			if got != want {
+				t.Fatalf("MulBlocks: %d blocks: expected %v, got %v",
+					n, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !amd64 || !gc || purego || noasm || tinygo
+
+package field
+
+func reverseBlocks(dst, src []byte) {
+	reverseBlocksGeneric(dst, src)
+}
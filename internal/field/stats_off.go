@@ -0,0 +1,23 @@
+//go:build !polyvalstats
+
+package field
+
+// countBlocks records a call to MulBlocks that writes nblocks
+// blocks.
+//
+// It does nothing unless the package is built with the
+// polyvalstats build tag.
+func countBlocks(nblocks int) {}
+
+// resetStats does nothing.
+func resetStats() {}
+
+// ReadStats returns the number of calls to MulBlocks that wrote
+// at least one block, the number of blocks they wrote, and how
+// many of those were in whole multiples of 16 blocks per call.
+//
+// ok is false if the package was built without the
+// polyvalstats build tag, in which case the counts are zero.
+func ReadStats() (calls, blocks, wideBlocks uint64, ok bool) {
+	return 0, 0, 0, false
+}
@@ -0,0 +1,49 @@
+//go:build polyvalstats
+
+package field
+
+import (
+	"sync/atomic"
+)
+
+// stats holds the counters reported by ReadStats.
+//
+// It is accessed atomically.
+var stats struct {
+	calls      uint64
+	blocks     uint64
+	wideBlocks uint64
+}
+
+// countBlocks records a call to MulBlocks that writes nblocks
+// blocks.
+//
+// The wide count is the number of blocks in whole multiples of
+// 16 in the call. It does not depend on the kernel: the generic
+// kernel, for example, uses 8-block strides, and the AVX-512
+// kernel is not used for short inputs.
+func countBlocks(nblocks int) {
+	atomic.AddUint64(&stats.calls, 1)
+	atomic.AddUint64(&stats.blocks, uint64(nblocks))
+	atomic.AddUint64(&stats.wideBlocks, uint64(nblocks&^15))
+}
+
+// resetStats sets the counters to zero.
+func resetStats() {
+	atomic.StoreUint64(&stats.calls, 0)
+	atomic.StoreUint64(&stats.blocks, 0)
+	atomic.StoreUint64(&stats.wideBlocks, 0)
+}
+
+// ReadStats returns the number of calls to MulBlocks that wrote
+// at least one block, the number of blocks they wrote, and how
+// many of those were in whole multiples of 16 blocks per call.
+//
+// ok is false if the package was built without the
+// polyvalstats build tag, in which case the counts are zero.
+func ReadStats() (calls, blocks, wideBlocks uint64, ok bool) {
+	return atomic.LoadUint64(&stats.calls),
+		atomic.LoadUint64(&stats.blocks),
+		atomic.LoadUint64(&stats.wideBlocks),
+		true
+}
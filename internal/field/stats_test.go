@@ -0,0 +1,29 @@
+package field
+
+import (
+	"testing"
+)
+
+// TestStatsInit tests that the work done to select a kernel at
+// init is not counted.
+func TestStatsInit(t *testing.T) {
+	defer selectKernel(Kernel)
+
+	t.Setenv("POLYVAL_KERNEL", "auto")
+	initKernel()
+	calls, blocks, wide, ok := ReadStats()
+	if !ok {
+		t.Skip("built without the polyvalstats tag")
+	}
+	if calls != 0 || blocks != 0 || wide != 0 {
+		t.Fatalf("expected zero counters, got %d, %d, %d", calls, blocks, wide)
+	}
+
+	var acc Element
+	var pow [16]Element
+	MulBlocks(&acc, &pow, make([]byte, 16*37))
+	calls, blocks, wide, _ = ReadStats()
+	if calls != 1 || blocks != 37 || wide != 32 {
+		t.Fatalf("expected 1, 37, 32, got %d, %d, %d", calls, blocks, wide)
+	}
+}
@@ -0,0 +1,23 @@
+// Code generated by command: go run asm.go -out out/field_amd64.s -stubs out/stub_amd64.go -pkg field. DO NOT EDIT.
+
+//go:build gc && !purego && !noasm && !tinygo
+
+package field
+
+//go:noescape
+func polymulAsm(acc *Element, key *Element)
+
+//go:noescape
+func polymulBlocksAsm(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func polymulAVX(acc *Element, key *Element)
+
+//go:noescape
+func polymulBlocksAVX(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func polymulBlocksAVX512(acc *Element, pow *[16]Element, input *byte, nblocks int)
+
+//go:noescape
+func reverseBlocksAsm(dst *byte, src *byte, nblocks int)
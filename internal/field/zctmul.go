@@ -1,10 +1,10 @@
 // Code generated by gen.go. DO NOT EDIT.
 
-package polyval
+package field
 
 import "math/bits"
 
-// ctmulGeneric returns the constant time 128-bit product of
+// ctmulGeneric64 returns the constant time 128-bit product of
 // x and y in GF(2^128).
 //
 // The idea comes from Thomas Pornin's constant-time blog post
@@ -13,7 +13,7 @@ import "math/bits"
 //
 // See https://www.bearssl.org/constanttime.html
 // See https://timtaubert.de/blog/2017/06/verified-binary-multiplication-for-ghash/
-func ctmulGeneric(x, y uint64) (z1, z0 uint64) {
+func ctmulGeneric64(x, y uint64) (z1, z0 uint64) {
 	// Split both x and y into 5 words with four-bit holes.
 	x0 := x & 0x1084210842108421
 	y0 := y & 0x1084210842108421
@@ -0,0 +1,109 @@
+package polyval
+
+import (
+	"errors"
+)
+
+// ErrUsageExceeded is returned when a Limited has written the
+// maximum number of blocks or messages.
+var ErrUsageExceeded = errors.New("polyval: usage limit exceeded")
+
+// Limited is a Polyval that limits how much data it hashes under
+// one key.
+//
+// It counts the blocks written with Update and the messages
+// started with Reset, including the first message. Once either
+// limit would be crossed, Update and Reset return
+// ErrUsageExceeded and leave the state unchanged. The counts
+// are never reset, so a Limited that has reached its limit must
+// be replaced with one that uses a new key.
+//
+// This helps AEADs built on POLYVAL enforce the usage bounds in
+// RFC 8452 section 9.
+type Limited struct {
+	p Polyval
+	// blocks and msgs are the number of blocks and messages
+	// written so far.
+	blocks, msgs uint64
+	// maxBlocks and maxMsgs are the limits, or zero for no
+	// limit.
+	maxBlocks, maxMsgs uint64
+}
+
+// NewLimited creates a Limited that writes at most maxBlocks
+// blocks and maxMessages messages. A limit of zero means no
+// limit.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func NewLimited(key []byte, maxBlocks, maxMessages uint64) (*Limited, error) {
+	l := &Limited{
+		msgs:      1,
+		maxBlocks: maxBlocks,
+		maxMsgs:   maxMessages,
+	}
+	if err := l.p.Init(key); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Size returns the size of a POLYVAL digest.
+func (l *Limited) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (l *Limited) BlockSize() int {
+	return 16
+}
+
+// Blocks returns the number of blocks written so far.
+func (l *Limited) Blocks() uint64 {
+	return l.blocks
+}
+
+// Messages returns the number of messages started so far.
+func (l *Limited) Messages() uint64 {
+	return l.msgs
+}
+
+// Reset sets the hash to its original state and starts a new
+// message.
+//
+// It returns ErrUsageExceeded if the limit on messages has been
+// reached.
+func (l *Limited) Reset() error {
+	if l.maxMsgs != 0 && l.msgs >= l.maxMsgs {
+		return ErrUsageExceeded
+	}
+	l.msgs++
+	l.p.Reset()
+	return nil
+}
+
+// Update writes one or more blocks to the running hash.
+//
+// It returns ErrUsageExceeded if writing blocks would cross the
+// limit on blocks.
+//
+// If len(block) is not divisible by BlockSize, Update will panic.
+func (l *Limited) Update(blocks []byte) error {
+	if len(blocks)%16 != 0 {
+		panic("polyval: invalid input length")
+	}
+	n := uint64(len(blocks) / 16)
+	if l.maxBlocks != 0 && n > l.maxBlocks-l.blocks {
+		return ErrUsageExceeded
+	}
+	l.blocks += n
+	l.p.Update(blocks)
+	return nil
+}
+
+// Sum appends the current hash to b and returns the resulting
+// slice.
+//
+// It does not change the underlying hash state.
+func (l *Limited) Sum(b []byte) []byte {
+	return l.p.Sum(b)
+}
@@ -0,0 +1,100 @@
+package polyval
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestLimited tests that Limited matches Polyval and enforces
+// its limits.
+func TestLimited(t *testing.T) {
+	runTests(t, testLimited)
+}
+
+func testLimited(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	msg := make([]byte, 16*40)
+	rng.Read(msg)
+
+	l, err := NewLimited(key, 100, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := New(key)
+	for i := 0; i < 2; i++ {
+		if err := l.Update(msg); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		p.Update(msg)
+		if got, want := l.Sum(nil), p.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+		if err := l.Reset(); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		p.Reset()
+	}
+	if l.Blocks() != 80 || l.Messages() != 3 {
+		t.Fatalf("expected 80 blocks and 3 messages, got %d and %d",
+			l.Blocks(), l.Messages())
+	}
+
+	// Crossing the block limit does not change the state.
+	want := l.Sum(nil)
+	if err := l.Update(msg); !errors.Is(err, ErrUsageExceeded) {
+		t.Fatalf("expected ErrUsageExceeded, got %v", err)
+	}
+	if got := l.Sum(nil); !bytes.Equal(got, want) {
+		t.Fatalf("expected %x, got %x", want, got)
+	}
+	if l.Blocks() != 80 {
+		t.Fatalf("expected 80 blocks, got %d", l.Blocks())
+	}
+	// Reaching it exactly is allowed.
+	if err := l.Update(msg[:16*20]); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Update(msg[:16]); !errors.Is(err, ErrUsageExceeded) {
+		t.Fatalf("expected ErrUsageExceeded, got %v", err)
+	}
+	if err := l.Update(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Reset(); !errors.Is(err, ErrUsageExceeded) {
+		t.Fatalf("expected ErrUsageExceeded, got %v", err)
+	}
+	if l.Messages() != 3 {
+		t.Fatalf("expected 3 messages, got %d", l.Messages())
+	}
+}
+
+// TestLimitedUnlimited tests that a limit of zero means no
+// limit.
+func TestLimitedUnlimited(t *testing.T) {
+	key := make([]byte, 16)
+	key[0] = 1
+	l, err := NewLimited(key, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.blocks = ^uint64(0) - 1
+	l.msgs = ^uint64(0) - 1
+	if err := l.Update(make([]byte, 16)); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLimited(make([]byte, 16), 1, 1); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package locked
+
+// dontDump does nothing, since not every platform can exclude
+// memory from core dumps.
+func dontDump(b []byte) error {
+	return nil
+}
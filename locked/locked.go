@@ -0,0 +1,32 @@
+// Package locked computes POLYVAL with the key and its powers
+// stored in locked memory.
+//
+// The state lives in its own pages, outside of the Go heap,
+// between two inaccessible guard pages. The pages are locked
+// with mlock so that they are never written to swap and, on
+// Linux, are excluded from core dumps. The garbage collector
+// never copies or scans them, and Close zeroes them before
+// returning them to the operating system.
+//
+// Each Polyval uses three pages of memory and counts against
+// RLIMIT_MEMLOCK, so this package is meant for long-lived keys,
+// not for one Polyval per message. On platforms other than
+// Linux, macOS, and the BSDs, New returns an error that wraps
+// ErrUnsupported.
+package locked
+
+import (
+	"errors"
+)
+
+// Size is the size in bytes of a POLYVAL checksum.
+const Size = 16
+
+// ErrUnsupported is returned when the platform does not support
+// locked memory.
+var ErrUnsupported = errors.New("locked: locked memory is not supported")
+
+// ErrClosed is returned when a Polyval is closed more than
+// once. Reset, Update, and Sum panic with ErrClosed when called
+// after Close.
+var ErrClosed = errors.New("locked: Polyval is closed")
@@ -0,0 +1,41 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package locked
+
+// Polyval is a polyval.Polyval stored in locked memory.
+//
+// It is only supported on Linux, macOS, and the BSDs.
+type Polyval struct{}
+
+// New returns ErrUnsupported.
+func New(key []byte) (*Polyval, error) {
+	return nil, ErrUnsupported
+}
+
+// Close returns ErrUnsupported.
+func (p *Polyval) Close() error {
+	return ErrUnsupported
+}
+
+// Size returns the size of a POLYVAL digest.
+func (p *Polyval) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (p *Polyval) BlockSize() int {
+	return 16
+}
+
+// Reset does nothing.
+func (p *Polyval) Reset() {}
+
+// Update panics.
+func (p *Polyval) Update(blocks []byte) {
+	panic(ErrUnsupported)
+}
+
+// Sum panics.
+func (p *Polyval) Sum(b []byte) []byte {
+	panic(ErrUnsupported)
+}
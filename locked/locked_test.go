@@ -0,0 +1,65 @@
+package locked
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval"
+)
+
+// newOrSkip is like New, but skips the test if locked memory is
+// not supported.
+func newOrSkip(t *testing.T, key []byte) *Polyval {
+	p, err := New(key)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// TestMatchesPolyval tests that Polyval matches package
+// polyval.
+func TestMatchesPolyval(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	buf := make([]byte, 16*300)
+	rng.Read(buf)
+
+	p := newOrSkip(t, key)
+	defer p.Close()
+	for _, nblocks := range []int{0, 1, 2, 15, 16, 17, 100, 300} {
+		data := buf[:16*nblocks]
+		want := polyval.Sum(key, data)
+
+		p.Reset()
+		p.Update(data)
+		if got := p.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("%d blocks: expected %x, got %x", nblocks, want, got)
+		}
+	}
+}
+
+// TestInvalidKey tests that New rejects invalid keys.
+func TestInvalidKey(t *testing.T) {
+	for _, key := range [][]byte{
+		make([]byte, 16),
+		make([]byte, 15),
+		nil,
+	} {
+		p, err := New(key)
+		if err == nil {
+			p.Close()
+			t.Fatalf("%x: expected an error", key)
+		}
+	}
+}
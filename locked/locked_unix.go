@@ -0,0 +1,137 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package locked
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/ericlagergren/polyval"
+)
+
+// Polyval is a polyval.Polyval stored in locked memory.
+//
+// It must be closed with Close, which zeroes the memory.
+type Polyval struct {
+	// p points into the middle page of mem.
+	p *polyval.Polyval
+	// mem is the guard page, the locked page, and the guard
+	// page.
+	mem []byte
+}
+
+// New creates a Polyval.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+// The caller remains responsible for the memory holding key.
+func New(key []byte) (*Polyval, error) {
+	page := os.Getpagesize()
+	size := int(unsafe.Sizeof(polyval.Polyval{}))
+	if size > page {
+		return nil, ErrUnsupported
+	}
+	mem, err := unix.Mmap(-1, 0, 3*page,
+		unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, fmt.Errorf("locked: mmap: %w", err)
+	}
+	p := &Polyval{mem: mem}
+	if err := p.lock(page); err != nil {
+		unix.Munmap(mem)
+		return nil, err
+	}
+
+	// Put the state at the end of the page so that writing
+	// past it faults on the guard page. polyval.Polyval does
+	// not contain any pointers, so it can live outside of the
+	// Go heap.
+	data := mem[page : 2*page]
+	p.p = (*polyval.Polyval)(unsafe.Pointer(&data[len(data)-size]))
+	if err := p.p.Init(key); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// lock locks the middle page of p.mem and makes the other two
+// inaccessible.
+func (p *Polyval) lock(page int) error {
+	if err := unix.Mprotect(p.mem[:page], unix.PROT_NONE); err != nil {
+		return fmt.Errorf("locked: mprotect: %w", err)
+	}
+	if err := unix.Mprotect(p.mem[2*page:], unix.PROT_NONE); err != nil {
+		return fmt.Errorf("locked: mprotect: %w", err)
+	}
+	data := p.mem[page : 2*page]
+	if err := unix.Mlock(data); err != nil {
+		return fmt.Errorf("locked: mlock: %w", err)
+	}
+	if err := dontDump(data); err != nil {
+		unix.Munlock(data)
+		return fmt.Errorf("locked: madvise: %w", err)
+	}
+	return nil
+}
+
+// Close zeroes and releases the memory holding p.
+//
+// Closing p more than once returns ErrClosed. Calling Reset,
+// Update, or Sum after Close panics with ErrClosed.
+func (p *Polyval) Close() error {
+	if p.mem == nil {
+		return ErrClosed
+	}
+	if p.p != nil {
+		p.p.Wipe()
+		p.p = nil
+	}
+	page := len(p.mem) / 3
+	unix.Munlock(p.mem[page : 2*page])
+	err := unix.Munmap(p.mem)
+	p.mem = nil
+	return err
+}
+
+// Size returns the size of a POLYVAL digest.
+func (p *Polyval) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (p *Polyval) BlockSize() int {
+	return 16
+}
+
+// Reset sets the hash to its original state.
+func (p *Polyval) Reset() {
+	p.checkOpen()
+	p.p.Reset()
+}
+
+// Update writes one or more blocks to the running hash.
+//
+// If len(block) is not divisible by BlockSize, Update will panic.
+func (p *Polyval) Update(blocks []byte) {
+	p.checkOpen()
+	p.p.Update(blocks)
+}
+
+// Sum appends the current hash to b and returns the resulting
+// slice.
+//
+// It does not change the underlying hash state.
+func (p *Polyval) Sum(b []byte) []byte {
+	p.checkOpen()
+	return p.p.Sum(b)
+}
+
+// checkOpen panics with ErrClosed if p has been closed.
+func (p *Polyval) checkOpen() {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+}
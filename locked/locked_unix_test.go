@@ -0,0 +1,55 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package locked
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestClose tests that Close zeroes the state and that closing
+// twice returns ErrClosed.
+func TestClose(t *testing.T) {
+	key := make([]byte, 16)
+	key[0] = 1
+	p := newOrSkip(t, key)
+	p.Update(make([]byte, 16))
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p.p != nil || p.mem != nil {
+		t.Fatal("Close did not release the memory")
+	}
+	if err := p.Close(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// TestUseAfterClose tests that using a Polyval after Close
+// panics with ErrClosed.
+func TestUseAfterClose(t *testing.T) {
+	key := make([]byte, 16)
+	key[0] = 1
+	p := newOrSkip(t, key)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		fn   func()
+	}{
+		{"Reset", func() { p.Reset() }},
+		{"Update", func() { p.Update(make([]byte, 16)) }},
+		{"Sum", func() { p.Sum(nil) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrClosed) {
+					t.Fatalf("expected panic with ErrClosed, got %v", err)
+				}
+			}()
+			tc.fn()
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package polyval
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// format is a way of serializing a Polyval.
+type format struct {
+	name      string
+	marshal   func(p *Polyval) ([]byte, error)
+	unmarshal func(p *Polyval, data []byte) error
+}
+
+// formats returns every serialization format.
+func formats(wrap []byte) []format {
+	prefix := []byte("prefix")
+	return []format{
+		{
+			name:      "MarshalBinary",
+			marshal:   (*Polyval).MarshalBinary,
+			unmarshal: (*Polyval).UnmarshalBinary,
+		},
+		{
+			name: "AppendBinary",
+			marshal: func(p *Polyval) ([]byte, error) {
+				b, err := p.AppendBinary(append([]byte(nil), prefix...))
+				if err != nil {
+					return nil, err
+				}
+				return b[len(prefix):], nil
+			},
+			unmarshal: (*Polyval).UnmarshalBinary,
+		},
+		{
+			name: "MarshalBinarySealed",
+			marshal: func(p *Polyval) ([]byte, error) {
+				return p.MarshalBinarySealed(wrap)
+			},
+			unmarshal: func(p *Polyval, data []byte) error {
+				return p.UnmarshalBinarySealed(wrap, data)
+			},
+		},
+	}
+}
+
+// TestMarshalRoundTrip tests that every serialization format
+// restores a state that produces the same digests as the
+// original for any subsequent input, including when one format
+// is converted to another.
+func TestMarshalRoundTrip(t *testing.T) {
+	runTests(t, testMarshalRoundTrip)
+}
+
+func testMarshalRoundTrip(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	wrap := make([]byte, 16)
+	rng.Read(wrap)
+	fs := formats(wrap)
+
+	// update writes the same random blocks to every Polyval in
+	// ps and checks that their digests agree.
+	update := func(i int, ps map[string]*Polyval, want *Polyval) {
+		for j := 0; j < 4; j++ {
+			blocks := make([]byte, 16*rng.Intn(40))
+			rng.Read(blocks)
+			want.Update(blocks)
+			for name, p := range ps {
+				p.Update(blocks)
+				if got := p.Sum(nil); !bytes.Equal(got, want.Sum(nil)) {
+					t.Fatalf("#%d: %s: update %d: expected %x, got %x",
+						i, name, j, want.Sum(nil), got)
+				}
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		orig, _ := New(key)
+		blocks := make([]byte, 16*rng.Intn(40))
+		rng.Read(blocks)
+		orig.Update(blocks)
+
+		ps := make(map[string]*Polyval)
+		for _, f := range fs {
+			data, err := f.marshal(orig)
+			if err != nil {
+				t.Fatalf("#%d: %s: %v", i, f.name, err)
+			}
+			var p Polyval
+			if err := f.unmarshal(&p, data); err != nil {
+				t.Fatalf("#%d: %s: %v", i, f.name, err)
+			}
+			if !StatesEqual(&p, orig) || p.pow != orig.pow {
+				t.Fatalf("#%d: %s: restored state differs", i, f.name)
+			}
+			ps[f.name] = &p
+
+			// Convert to every other format.
+			for _, g := range fs {
+				data, err := g.marshal(&p)
+				if err != nil {
+					t.Fatalf("#%d: %s -> %s: %v", i, f.name, g.name, err)
+				}
+				var q Polyval
+				if err := g.unmarshal(&q, data); err != nil {
+					t.Fatalf("#%d: %s -> %s: %v", i, f.name, g.name, err)
+				}
+				if !StatesEqual(&q, orig) || q.pow != orig.pow {
+					t.Fatalf("#%d: %s -> %s: restored state differs",
+						i, f.name, g.name)
+				}
+				ps[f.name+" -> "+g.name] = &q
+			}
+		}
+		update(i, ps, orig)
+	}
+}
@@ -0,0 +1,234 @@
+package polyval
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// minParallelBlocks is the smallest number of blocks
+// SumParallel gives to a goroutine.
+//
+// Below this, starting a goroutine costs more than hashing
+// the blocks.
+//
+// It is only modified by tests.
+var minParallelBlocks = 64 * 1024 / 16
+
+// SumParallel returns the POLYVAL hash of data using up to
+// workers goroutines.
+//
+// The result is identical to Sum(key, data). Splitting the
+// input only pays off for inputs much larger than 64 KiB, like
+// multi-gigabyte files. If workers is less than one,
+// runtime.GOMAXPROCS(0) goroutines are used.
+//
+// If len(data) is not divisible by 16, SumParallel will panic.
+func SumParallel(key, data []byte, workers int) [Size]byte {
+	var p Polyval
+	if err := p.Init(key); err != nil {
+		panic(err)
+	}
+	if len(data)%16 != 0 {
+		panic("polyval: invalid input length")
+	}
+
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	nblocks := len(data) / 16
+	if n := nblocks / minParallelBlocks; n < workers {
+		workers = n
+	}
+	if workers <= 1 {
+		p.Update(data)
+		return *(*[Size]byte)(p.Sum(nil))
+	}
+
+	// Split the input into equal chunks, rounded up to a
+	// multiple of the 16-block stride. The last chunk gets
+	// whatever is left over.
+	chunk := (nblocks + workers - 1) / workers
+	chunk = (chunk + 15) &^ 15
+	workers = (nblocks + chunk - 1) / chunk
+
+	sums := make([]field.Element, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := range sums {
+		i := i
+		go func() {
+			defer wg.Done()
+			b := data[i*chunk*16:]
+			if len(b) > chunk*16 {
+				b = b[:chunk*16]
+			}
+			field.MulBlocks(&sums[i], &p.pow, b)
+		}()
+	}
+	wg.Wait()
+
+	p.y = combine(p.h, sums, chunk, nblocks-(workers-1)*chunk)
+	return *(*[Size]byte)(p.Sum(nil))
+}
+
+// defaultChunkSize is the chunk size used by
+// SumReaderAtParallel when none is given.
+const defaultChunkSize = 1 << 20
+
+// SumFileParallel returns the POLYVAL hash of the named file.
+//
+// It is like SumReaderAtParallel.
+func SumFileParallel(key []byte, name string, workers, chunkSize int) ([Size]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return [Size]byte{}, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return [Size]byte{}, err
+	}
+	return SumReaderAtParallel(key, f, fi.Size(), workers, chunkSize)
+}
+
+// SumReaderAtParallel returns the POLYVAL hash of the first size
+// bytes of r using up to workers goroutines.
+//
+// The input is split into chunks of chunkSize bytes, rounded up
+// to a multiple of 256, and each goroutine reads and hashes one
+// chunk at a time with its own chunkSize buffer. The result is
+// identical to Sum(key, data), where data is the input. This is
+// useful for large files on storage that serves concurrent reads
+// well, like NVMe drives or memory-mapped files.
+//
+// If workers is less than one, runtime.GOMAXPROCS(0) goroutines
+// are used. If chunkSize is less than one, a 1 MiB chunk is
+// used.
+//
+// size must be a multiple of 16.
+func SumReaderAtParallel(key []byte, r io.ReaderAt, size int64, workers, chunkSize int) ([Size]byte, error) {
+	var p Polyval
+	if err := p.Init(key); err != nil {
+		return [Size]byte{}, err
+	}
+	if size < 0 || size%16 != 0 {
+		return [Size]byte{}, errors.New("polyval: invalid input length")
+	}
+	if size == 0 {
+		return *(*[Size]byte)(p.Sum(nil)), nil
+	}
+
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunkSize < 1 {
+		chunkSize = defaultChunkSize
+	}
+	// Keep every chunk but the last a multiple of the 16-block
+	// stride.
+	chunkSize = (chunkSize + 255) &^ 255
+	if int64(chunkSize) > size {
+		chunkSize = int(size)
+	}
+	nchunks := int((size + int64(chunkSize) - 1) / int64(chunkSize))
+	if workers > nchunks {
+		workers = nchunks
+	}
+
+	var (
+		sums = make([]field.Element, nchunks)
+		next int64 // next chunk, updated atomically
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		err  error
+	)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, chunkSize)
+			for {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= nchunks {
+					return
+				}
+				off := int64(i) * int64(chunkSize)
+				b := buf
+				if n := size - off; n < int64(len(b)) {
+					b = b[:n]
+				}
+				n, rerr := r.ReadAt(b, off)
+				if n == len(b) {
+					// ReadAt may return io.EOF with a full
+					// read at the end of the input.
+					rerr = nil
+				} else if rerr == nil || rerr == io.EOF {
+					rerr = io.ErrUnexpectedEOF
+				}
+				if rerr != nil {
+					mu.Lock()
+					if err == nil {
+						err = rerr
+					}
+					mu.Unlock()
+					// Stop the other goroutines.
+					atomic.StoreInt64(&next, int64(nchunks))
+					return
+				}
+				field.MulBlocks(&sums[i], &p.pow, b)
+			}
+		}()
+	}
+	wg.Wait()
+	if err != nil {
+		return [Size]byte{}, err
+	}
+
+	last := size - int64(nchunks-1)*int64(chunkSize)
+	p.y = combine(p.h, sums, chunkSize/16, int(last/16))
+	return *(*[Size]byte)(p.Sum(nil)), nil
+}
+
+// combine returns the hash of a message that was split into
+// chunks, where sums[i] is the hash of the i-th chunk. Each
+// chunk is chunk blocks long, except for the last, which is last
+// blocks long.
+func combine(h field.Element, sums []field.Element, chunk, last int) field.Element {
+	// POLYVAL is linear, so the hash of A || B is
+	//
+	//    POLYVAL(H, A)*H^len(B) + POLYVAL(H, B)
+	//
+	// where len(B) is in blocks.
+	var y field.Element
+	hn := powH(h, chunk)
+	for i, s := range sums {
+		if i == len(sums)-1 && last != chunk {
+			hn = powH(h, last)
+		}
+		if i > 0 {
+			field.Mul(&y, &hn)
+		}
+		y.Lo ^= s.Lo
+		y.Hi ^= s.Hi
+	}
+	return y
+}
+
+// powH returns h^n for n > 0.
+func powH(h field.Element, n int) field.Element {
+	z := h
+	for i := bits.Len(uint(n)) - 2; i >= 0; i-- {
+		field.Square(&z)
+		if n>>uint(i)&1 == 1 {
+			field.Mul(&z, &h)
+		}
+	}
+	return z
+}
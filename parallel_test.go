@@ -0,0 +1,161 @@
+package polyval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// TestSumParallel tests that SumParallel matches Sum.
+func TestSumParallel(t *testing.T) {
+	runTests(t, testSumParallel)
+}
+
+func testSumParallel(t *testing.T) {
+	old := minParallelBlocks
+	t.Cleanup(func() {
+		minParallelBlocks = old
+	})
+	minParallelBlocks = 1
+
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	buf := make([]byte, 16*1000)
+	rng.Read(buf)
+
+	for _, nblocks := range []int{0, 1, 15, 16, 17, 63, 64, 100, 1000} {
+		data := buf[:16*nblocks]
+		want := Sum(key, data)
+		for _, workers := range []int{-1, 0, 1, 2, 3, 7, 16, 2000} {
+			got := SumParallel(key, data, workers)
+			if got != want {
+				t.Fatalf("%d blocks, %d workers: expected %x, got %x",
+					nblocks, workers, want, got)
+			}
+		}
+	}
+}
+
+// TestPowH tests that powH(h, n) matches n-1 multiplications
+// by h.
+func TestPowH(t *testing.T) {
+	h := field.Element{Lo: 0x0123456789abcdef, Hi: 0xfedcba9876543210}
+	want := h
+	for n := 1; n < 100; n++ {
+		if got := powH(h, n); got != want {
+			t.Fatalf("h^%d: expected %v, got %v", n, want, got)
+		}
+		field.Mul(&want, &h)
+	}
+}
+
+func BenchmarkSumParallel(b *testing.B) {
+	key := unhex("01000000000000000000000000000000")
+	x := make([]byte, 64<<20)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(x)))
+			for i := 0; i < b.N; i++ {
+				sumSink = SumParallel(key, x, workers)
+			}
+		})
+	}
+}
+
+var sumSink [Size]byte
+
+// TestSumReaderAtParallel tests that SumReaderAtParallel and
+// SumFileParallel match Sum.
+func TestSumReaderAtParallel(t *testing.T) {
+	runTests(t, testSumReaderAtParallel)
+}
+
+func testSumReaderAtParallel(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	buf := make([]byte, 16*1000)
+	rng.Read(buf)
+
+	for _, nblocks := range []int{0, 1, 15, 16, 17, 63, 64, 100, 1000} {
+		data := buf[:16*nblocks]
+		want := Sum(key, data)
+		for _, chunkSize := range []int{-1, 0, 1, 256, 300, 1024, 1 << 20} {
+			for _, workers := range []int{-1, 0, 1, 2, 3, 7} {
+				r := bytes.NewReader(data)
+				got, err := SumReaderAtParallel(key, r, int64(len(data)), workers, chunkSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("%d blocks, %d-byte chunks, %d workers: expected %x, got %x",
+						nblocks, chunkSize, workers, want, got)
+				}
+			}
+		}
+	}
+
+	name := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(name, buf, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := SumFileParallel(key, name, 4, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Sum(key, buf); got != want {
+		t.Fatalf("expected %x, got %x", want, got)
+	}
+}
+
+// TestSumReaderAtParallelErrors tests that SumReaderAtParallel
+// reports invalid lengths and read errors.
+func TestSumReaderAtParallelErrors(t *testing.T) {
+	key := unhex("01000000000000000000000000000000")
+	data := make([]byte, 16*100)
+
+	for _, size := range []int64{-16, 17} {
+		_, err := SumReaderAtParallel(key, bytes.NewReader(data), size, 1, 0)
+		if err == nil {
+			t.Fatalf("%d: expected an error", size)
+		}
+	}
+
+	// Input shorter than size.
+	_, err := SumReaderAtParallel(key, bytes.NewReader(data), int64(len(data)+16), 2, 256)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+
+	errRead := errors.New("read error")
+	r := readerAtFunc(func(p []byte, off int64) (int, error) {
+		if off >= 512 {
+			return 0, errRead
+		}
+		return len(p), nil
+	})
+	_, err = SumReaderAtParallel(key, r, int64(len(data)), 3, 256)
+	if err != errRead {
+		t.Fatalf("expected %v, got %v", errRead, err)
+	}
+}
+
+type readerAtFunc func(p []byte, off int64) (int, error)
+
+func (fn readerAtFunc) ReadAt(p []byte, off int64) (int, error) {
+	return fn(p, off)
+}
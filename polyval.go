@@ -4,7 +4,6 @@
 // of GHASH.
 //
 // [rfc8452]: https://datatracker.ietf.org/doc/html/rfc8452#section-3
-// [gueron]: https://crypto.stanford.edu/RealWorldCrypto/slides/gueron.pdf
 package polyval
 
 import (
@@ -12,11 +11,13 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"math/bits"
 
 	"github.com/ericlagergren/subtle"
-)
 
-//go:generate go run github.com/ericlagergren/polyval/internal/cmd/gen ctmul
+	"github.com/ericlagergren/polyval/internal/field"
+)
 
 const (
 	// Size is the size in bytes of a POLYVAL checksum.
@@ -33,6 +34,23 @@ func Sum(key, data []byte) [Size]byte {
 	return *(*[Size]byte)(p.Sum(nil))
 }
 
+// VerifySum reports whether tag is the POLYVAL hash of data.
+//
+// It compares the hash in constant time and zeroes the hash and
+// the key's powers before returning.
+func VerifySum(key, data, tag []byte) bool {
+	var p Polyval
+	defer p.Wipe()
+	if err := p.Init(key); err != nil {
+		panic(err)
+	}
+	p.Update(data)
+	var sum [Size]byte
+	defer subtle.Wipe(sum[:])
+	p.Sum(sum[:0])
+	return subtle.ConstantTimeCompare(sum[:], tag) == 1
+}
+
 // Polyval is an implementation of POLYVAL.
 //
 // It operates similar to the standard library's Hash interface,
@@ -54,12 +72,12 @@ type Polyval struct {
 	// non-constant time comparisons.
 	_ [0]func()
 	// h is the hash key.
-	h fieldElement
+	h field.Element
 	// y is the running state.
-	y fieldElement
+	y field.Element
 	// pow is a pre-computed table of powers of h for writing
-	// groups of eight blocks.
-	pow [8]fieldElement
+	// groups of sixteen blocks.
+	pow [16]field.Element
 }
 
 var (
@@ -67,6 +85,15 @@ var (
 	_ encoding.BinaryUnmarshaler
 )
 
+const (
+	// marshaledPow is the number of powers of h stored by
+	// MarshalBinary.
+	marshaledPow = 8
+	// marshaledSize is the size in bytes of the output of
+	// MarshalBinary.
+	marshaledSize = 16 * (2 + marshaledPow)
+)
+
 // New creates a Polyval.
 //
 // The key must be exactly 16 bytes long and cannot be all zero.
@@ -78,24 +105,71 @@ func New(key []byte) (*Polyval, error) {
 	return &p, nil
 }
 
+// NewFromReader creates a Polyval with a key read from r.
+//
+// It reads exactly 16 bytes from r and zeroes its copy of them
+// before returning. The key cannot be all zero.
+func NewFromReader(r io.Reader) (*Polyval, error) {
+	var key [16]byte
+	defer subtle.Wipe(key[:])
+	if _, err := io.ReadFull(r, key[:]); err != nil {
+		return nil, err
+	}
+	return New(key[:])
+}
+
 // Init initializes a Polyval.
 //
 // The key must be exactly 16 bytes long and cannot be all zero.
 func (p *Polyval) Init(key []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	p.h.SetBytes(key)
+	p.pow[len(p.pow)-1] = p.h
+	p.expandPow(len(p.pow) - 1)
+	return nil
+}
+
+// checkKey returns an error if key is not a valid POLYVAL key.
+func checkKey(key []byte) error {
 	if len(key) != 16 {
 		return fmt.Errorf("invalid key size: %d", len(key))
 	}
 	if subtle.ConstantTimeBigEndianZero(key) == 1 {
 		return errors.New("the zero key is invalid")
 	}
+	return nil
+}
 
-	p.h.setBytes(key)
-	p.pow[len(p.pow)-1] = p.h
-	for i := len(p.pow) - 2; i >= 0; i-- {
-		p.pow[i] = p.h
-		polymul(&p.pow[i], &p.pow[i+1])
+// expandPow computes pow[:n] from pow[n:].
+func (p *Polyval) expandPow(n int) {
+	expandPow(&p.pow, 0, n)
+}
+
+// expandPow computes pow[lo:n] from pow[n:], where pow[i] is
+// h^(len(pow)-i).
+//
+// Each power is the product of the largest smaller power of
+// two and the remainder, so the multiplications form a tree
+// of depth log2(len(pow)) rather than a chain and independent
+// multiplications can overlap.
+func expandPow(pow *[16]field.Element, lo, n int) {
+	for i := n - 1; i >= lo; i-- {
+		e := len(pow) - i
+		a := 1 << (bits.Len(uint(e-1)) - 1)
+		// Go copies Elements with two 8-byte stores, which
+		// the 16-byte loads in the assembly kernels cannot be
+		// forwarded from. copy uses 16-byte moves and cuts the
+		// cost of Init in half.
+		copy(pow[i:i+1], pow[len(pow)-a:])
+		if b := e - a; b == a {
+			field.Square(&pow[i])
+		} else {
+			field.Mul(&pow[i], &pow[len(pow)-b])
+		}
 	}
-	return nil
 }
 
 // Size returns the size of a POLYVAL digest.
@@ -110,7 +184,15 @@ func (p *Polyval) BlockSize() int {
 
 // Reset sets the hash to its original state.
 func (p *Polyval) Reset() {
-	p.y = fieldElement{}
+	p.y = field.Element{}
+}
+
+// Wipe zeroes the key, the powers of the key, and the running
+// hash.
+//
+// p must be initialized again before it is used.
+func (p *Polyval) Wipe() {
+	*p = Polyval{}
 }
 
 // Update writes one or more blocks to the running hash.
@@ -120,7 +202,35 @@ func (p *Polyval) Update(blocks []byte) {
 	if len(blocks)%16 != 0 {
 		panic("polyval: invalid input length")
 	}
-	polymulBlocks(&p.y, &p.pow, blocks)
+	field.MulBlocks(&p.y, &p.pow, blocks)
+}
+
+// UpdateGHASH is like Update, except that it reverses the bytes
+// of each block first.
+//
+// GHASH blocks are the byte-wise reverse of POLYVAL blocks, so
+// this accepts input framed for GHASH. Per RFC 8452 appendix A,
+// the GHASH of the input with key K is the byte-wise reverse of
+// Sum if p was initialized with mulX_POLYVAL(ByteReverse(K)).
+//
+// If len(block) is not divisible by BlockSize, UpdateGHASH will
+// panic.
+func (p *Polyval) UpdateGHASH(blocks []byte) {
+	if len(blocks)%16 != 0 {
+		panic("polyval: invalid input length")
+	}
+	// A multiple of the widest stride, so that the reversed
+	// blocks can still be written with the wide kernels.
+	var buf [16 * 64]byte
+	for len(blocks) > 0 {
+		n := len(blocks)
+		if n > len(buf) {
+			n = len(buf)
+		}
+		field.ReverseBlocks(buf[:n], blocks[:n])
+		field.MulBlocks(&p.y, &p.pow, buf[:n])
+		blocks = blocks[n:]
+	}
 }
 
 // Sum appends the current hash to b and returns the resulting
@@ -129,148 +239,119 @@ func (p *Polyval) Update(blocks []byte) {
 // It does not change the underlying hash state.
 func (p *Polyval) Sum(b []byte) []byte {
 	ret, out := subtle.SliceForAppend(b, 16)
-	binary.LittleEndian.PutUint64(out[0:8], p.y.lo)
-	binary.LittleEndian.PutUint64(out[8:16], p.y.hi)
+	binary.LittleEndian.PutUint64(out[0:8], p.y.Lo)
+	binary.LittleEndian.PutUint64(out[8:16], p.y.Hi)
 	return ret
 }
 
-// MarshalBinary implements BinaryMarshaler.
+// StatesEqual reports, in constant time, whether a and b have
+// the same key and running hash.
 //
-// It does not return an error.
-func (p *Polyval) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, 16*(2+len(p.pow)))
-	binary.LittleEndian.PutUint64(buf[0:], p.h.lo)
-	binary.LittleEndian.PutUint64(buf[8:], p.h.hi)
-	binary.LittleEndian.PutUint64(buf[16:], p.y.lo)
-	binary.LittleEndian.PutUint64(buf[24:], p.y.hi)
-	for i, x := range p.pow {
-		binary.LittleEndian.PutUint64(buf[32+(i*16):], x.lo)
-		binary.LittleEndian.PutUint64(buf[40+(i*16):], x.hi)
-	}
-	return buf, nil
+// Unlike comparing the output of Sum, it does not copy the
+// running hash out of either Polyval.
+func StatesEqual(a, b *Polyval) bool {
+	v := (a.h.Lo ^ b.h.Lo) | (a.h.Hi ^ b.h.Hi) |
+		(a.y.Lo ^ b.y.Lo) | (a.y.Hi ^ b.y.Hi)
+	return isZero(v) == 1
 }
 
-// Unmarshalbinary implements BinaryUnmarshaler.
+// isZero returns 1 if x == 0 and 0 otherwise.
 //
-// data must be exactly 160 bytes.
-func (p *Polyval) UnmarshalBinary(data []byte) error {
-	if len(data) != 16*(2+len(p.pow)) {
-		return fmt.Errorf("invalid data size: %d", len(data))
-	}
-	p.h.lo = binary.LittleEndian.Uint64(data[0:8])
-	p.h.hi = binary.LittleEndian.Uint64(data[8:16])
-	p.y.lo = binary.LittleEndian.Uint64(data[16:24])
-	p.y.hi = binary.LittleEndian.Uint64(data[24:32])
-	for i, x := range p.pow {
-		x.lo = binary.LittleEndian.Uint64(data[32+(i*16):])
-		x.hi = binary.LittleEndian.Uint64(data[40+(i*16):])
-		p.pow[i] = x
-	}
-	return nil
+// It runs in constant time.
+func isZero(x uint64) int {
+	return subtle.ConstantTimeEq(int32(x|x>>32), 0)
 }
 
-func polymulGeneric(acc, key *fieldElement) {
-	x, y := key, acc
-	// We perform schoolbook multiplication of x and y:
-	//
-	// (x1,x0)*(y1,y0) = (x1*y1) + (x1*y0 + x0*y1) + (x0*y0)
-	//                      H         M       M         L
-	//
-	// The middle result (M) can be simplified with Karatsuba
-	// multiplication:
-	//
-	// (x1*y0 + x0*y1)  = (x1+x0) * (y1+x0) + (x1*y1) + (x0*y0)
-	//        M                                  H         L
-	//
-	// This requires one less 64-bit multiplication and reuses
-	// the existing results H and L. (H and L are added to M in
-	// the montgomery reduction; see x1 and x2.)
-	//
-	// This gives us a 256-bit product, X.
-	//
-	// Use the "Shift-XOR reflected reduction" method to reduce
-	// it modulo x^128 + x^127 + x^126 + x^121 + 1.
-	//
-	// This is faster than Gueron's "Fast reduction ..." method
-	// because Go doesn't have CMUL/PMULL intrinsics.
-	//
-	// See [gueron] page 17-19.
-	h1, h0 := ctmul(x.hi, y.hi)           // H
-	l1, l0 := ctmul(x.lo, y.lo)           // L
-	m1, m0 := ctmul(x.hi^x.lo, y.hi^y.lo) // M
-
-	m0 ^= l0 ^ h0
-	m1 ^= l1 ^ h1
-
-	l1 ^= m0 ^ (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
-	h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
-	h0 ^= m1 ^ (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
-	h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
-
-	y.hi = h1
-	y.lo = h0
-}
-
-func polymulBlocksGeneric(acc *fieldElement, pow *[8]fieldElement, blocks []byte) {
-	for (len(blocks)/16)%8 != 0 {
-		acc.lo ^= binary.LittleEndian.Uint64(blocks[0:8])
-		acc.hi ^= binary.LittleEndian.Uint64(blocks[8:16])
-		polymulGeneric(acc, &pow[len(pow)-1])
-		blocks = blocks[16:]
+// SumBatch writes the hash of each message in msgs to the
+// corresponding element of out.
+//
+// Each message is hashed independently, as if by Reset, Update,
+// and Sum, but the running hash is neither used nor changed.
+// It reuses the powers of the key in p, so it is cheaper than
+// calling New or Init for each message. The messages are not
+// interleaved: each one costs as much as Update does.
+//
+// SumBatch only reads p, so it can be called from multiple
+// goroutines at once, as long as none of them modify p.
+//
+// If len(out) < len(msgs) or the length of any message is not
+// divisible by BlockSize, SumBatch will panic.
+func (p *Polyval) SumBatch(msgs [][]byte, out [][Size]byte) {
+	if len(out) < len(msgs) {
+		panic("polyval: output too short")
 	}
-
-	const (
-		wide = 16 * len(pow)
-	)
-	for len(blocks) >= wide {
-		var h1, h0, l1, l0, m1, m0 uint64
-		for i, x := range pow {
-			var y fieldElement
-			y.setBytes(blocks[:16])
-			if i == 0 {
-				y.lo ^= acc.lo
-				y.hi ^= acc.hi
-			}
-
-			t1, t0 := ctmul(x.hi, y.hi)
-			h1 ^= t1
-			h0 ^= t0
-
-			t1, t0 = ctmul(x.lo, y.lo)
-			l1 ^= t1
-			l0 ^= t0
-
-			t1, t0 = ctmul(x.hi^x.lo, y.hi^y.lo)
-			m1 ^= t1
-			m0 ^= t0
-
-			blocks = blocks[16:]
+	for _, m := range msgs {
+		if len(m)%16 != 0 {
+			panic("polyval: invalid input length")
 		}
-
-		m0 ^= l0 ^ h0
-		m1 ^= l1 ^ h1
-
-		l1 ^= m0 ^ (l0 << 63) ^ (l0 << 62) ^ (l0 << 57)
-		h0 ^= l0 ^ (l0 >> 1) ^ (l0 >> 2) ^ (l0 >> 7)
-		h0 ^= m1 ^ (l1 << 63) ^ (l1 << 62) ^ (l1 << 57)
-		h1 ^= l1 ^ (l1 >> 1) ^ (l1 >> 2) ^ (l1 >> 7)
-
-		acc.hi = h1
-		acc.lo = h0
+	}
+	out = out[:len(msgs)]
+	for i, m := range msgs {
+		var y field.Element
+		field.MulBlocks(&y, &p.pow, m)
+		binary.LittleEndian.PutUint64(out[i][0:8], y.Lo)
+		binary.LittleEndian.PutUint64(out[i][8:16], y.Hi)
 	}
 }
 
-// fieldElement is a little-endian element in GF(2^128).
-type fieldElement struct {
-	lo, hi uint64
+// MarshalBinary implements BinaryMarshaler.
+//
+// It does not return an error.
+func (p *Polyval) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(make([]byte, 0, marshaledSize))
 }
 
-func (f fieldElement) String() string {
-	return fmt.Sprintf("%#0.16x%0.16x", f.hi, f.lo)
+// AppendBinary appends the encoding of MarshalBinary to b and
+// returns the resulting slice.
+//
+// Unlike MarshalBinary, it does not allocate if b has enough
+// capacity. It does not return an error.
+func (p *Polyval) AppendBinary(b []byte) ([]byte, error) {
+	// Only the last eight powers are stored, which keeps the
+	// encoding compatible with older versions of this
+	// package.
+	pow := p.pow[len(p.pow)-marshaledPow:]
+
+	ret, buf := subtle.SliceForAppend(b, marshaledSize)
+	binary.LittleEndian.PutUint64(buf[0:], p.h.Lo)
+	binary.LittleEndian.PutUint64(buf[8:], p.h.Hi)
+	binary.LittleEndian.PutUint64(buf[16:], p.y.Lo)
+	binary.LittleEndian.PutUint64(buf[24:], p.y.Hi)
+	for i, x := range pow {
+		binary.LittleEndian.PutUint64(buf[32+(i*16):], x.Lo)
+		binary.LittleEndian.PutUint64(buf[40+(i*16):], x.Hi)
+	}
+	return ret, nil
 }
 
-// setBytes sets z to the little-endian element p.
-func (z *fieldElement) setBytes(p []byte) {
-	z.lo = binary.LittleEndian.Uint64(p[0:8])
-	z.hi = binary.LittleEndian.Uint64(p[8:16])
+// Unmarshalbinary implements BinaryUnmarshaler.
+//
+// data must be exactly 160 bytes. UnmarshalBinary returns an
+// error without changing p if the key is invalid or the stored
+// powers of the key do not match the key.
+func (p *Polyval) UnmarshalBinary(data []byte) error {
+	if len(data) != marshaledSize {
+		return fmt.Errorf("invalid data size: %d", len(data))
+	}
+	// The key is stored the same way Init reads it.
+	var q Polyval
+	if err := q.Init(data[0:16]); err != nil {
+		return err
+	}
+	q.y.Lo = binary.LittleEndian.Uint64(data[16:24])
+	q.y.Hi = binary.LittleEndian.Uint64(data[24:32])
+
+	// The stored powers are redundant, so check them instead
+	// of trusting them.
+	var d uint64
+	pow := q.pow[len(q.pow)-marshaledPow:]
+	for i, x := range pow {
+		d |= x.Lo ^ binary.LittleEndian.Uint64(data[32+(i*16):])
+		d |= x.Hi ^ binary.LittleEndian.Uint64(data[40+(i*16):])
+	}
+	if isZero(d) != 1 {
+		return errors.New("invalid powers of the key")
+	}
+	*p = q
+	return nil
 }
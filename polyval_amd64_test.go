@@ -1,25 +1,98 @@
-//go:build amd64 && gc && !purego
+//go:build amd64 && gc && !purego && !noasm && !tinygo
 
 package polyval
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
 )
 
 func disableAsm(t *testing.T) {
-	old := haveAsm
+	old := field.HaveAsm
+	t.Cleanup(func() {
+		field.HaveAsm = old
+	})
+	field.HaveAsm = false
+}
+
+func disableAVX(t *testing.T) {
+	old := field.HaveAVX
+	t.Cleanup(func() {
+		field.HaveAVX = old
+	})
+	field.HaveAVX = false
+}
+
+func disableAVX512(t *testing.T) {
+	old := field.HaveAVX512
 	t.Cleanup(func() {
-		haveAsm = old
+		field.HaveAVX512 = old
 	})
-	haveAsm = false
+	field.HaveAVX512 = false
 }
 
 func runTests(t *testing.T, fn func(t *testing.T)) {
-	if haveAsm {
+	if field.HaveAsm {
 		t.Run("assembly", fn)
+		if field.HaveAVX512 {
+			t.Run("assemblyNoAVX512", func(t *testing.T) {
+				disableAVX512(t)
+				fn(t)
+			})
+		}
+		if field.HaveAVX {
+			t.Run("assemblyNoAVX", func(t *testing.T) {
+				disableAVX512(t)
+				disableAVX(t)
+				fn(t)
+			})
+		}
 	}
 	t.Run("generic", func(t *testing.T) {
 		disableAsm(t)
 		fn(t)
 	})
 }
+
+func BenchmarkPolyvalNoAVX512(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkPolyvalNoAVX512(b, n)
+		})
+	}
+}
+
+func benchmarkPolyvalNoAVX512(b *testing.B, nblocks int) {
+	if !field.HaveAVX512 {
+		b.Skip("CPU does not have AVX-512 and VPCLMULQDQ")
+	}
+	field.HaveAVX512 = false
+	b.Cleanup(func() {
+		field.HaveAVX512 = true
+	})
+	benchmarkPolyval(b, nblocks)
+}
+
+func BenchmarkPolyvalNoAVX(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkPolyvalNoAVX(b, n)
+		})
+	}
+}
+
+func benchmarkPolyvalNoAVX(b *testing.B, nblocks int) {
+	if !field.HaveAVX {
+		b.Skip("CPU does not have AVX")
+	}
+	avx512 := field.HaveAVX512
+	field.HaveAVX = false
+	field.HaveAVX512 = false
+	b.Cleanup(func() {
+		field.HaveAVX = true
+		field.HaveAVX512 = avx512
+	})
+	benchmarkPolyval(b, nblocks)
+}
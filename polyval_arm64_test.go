@@ -1,32 +1,34 @@
-//go:build arm64 && gc && !purego
+//go:build arm64 && gc && !purego && !noasm && !tinygo
 
 package polyval
 
 import (
 	"fmt"
 	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
 )
 
 func disableAsm(t *testing.T) {
-	old := haveAsm
+	old := field.HaveAsm
 	t.Cleanup(func() {
-		haveAsm = old
+		field.HaveAsm = old
 	})
-	haveAsm = false
+	field.HaveAsm = false
 }
 
 func disableSHA3(t *testing.T) {
-	old := haveSHA3
+	old := field.HaveSHA3
 	t.Cleanup(func() {
-		haveSHA3 = old
+		field.HaveSHA3 = old
 	})
-	haveSHA3 = false
+	field.HaveSHA3 = false
 }
 
 func runTests(t *testing.T, fn func(t *testing.T)) {
-	if haveAsm {
+	if field.HaveAsm {
 		t.Run("assembly", fn)
-		if haveSHA3 {
+		if field.HaveSHA3 {
 			t.Run("assemblyNoSHA3", func(t *testing.T) {
 				disableSHA3(t)
 				fn(t)
@@ -48,12 +50,12 @@ func BenchmarkPolyvalNoSHA3(b *testing.B) {
 }
 
 func benchmarkPolyvalNoSHA3(b *testing.B, nblocks int) {
-	if !haveSHA3 {
+	if !field.HaveSHA3 {
 		b.Skip("CPU does not have SHA-3 extensions")
 	}
-	haveSHA3 = false
+	field.HaveSHA3 = false
 	b.Cleanup(func() {
-		haveSHA3 = true
+		field.HaveSHA3 = true
 	})
 	benchmarkPolyval(b, nblocks)
 }
@@ -0,0 +1,61 @@
+//go:build arm && gc && !purego && !noasm && !tinygo
+
+package polyval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+func disableAsm(t *testing.T) {
+	old := field.HaveAsm
+	t.Cleanup(func() {
+		field.HaveAsm = old
+	})
+	field.HaveAsm = false
+}
+
+func disablePMULL(t *testing.T) {
+	old := field.HavePMULL
+	t.Cleanup(func() {
+		field.HavePMULL = old
+	})
+	field.HavePMULL = false
+}
+
+func runTests(t *testing.T, fn func(t *testing.T)) {
+	if field.HaveAsm {
+		t.Run("assembly", fn)
+		if field.HavePMULL {
+			t.Run("assemblyNoPMULL", func(t *testing.T) {
+				disablePMULL(t)
+				fn(t)
+			})
+		}
+	}
+	t.Run("generic", func(t *testing.T) {
+		disableAsm(t)
+		fn(t)
+	})
+}
+
+func BenchmarkPolyvalNoPMULL(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkPolyvalNoPMULL(b, n)
+		})
+	}
+}
+
+func benchmarkPolyvalNoPMULL(b *testing.B, nblocks int) {
+	if !field.HavePMULL {
+		b.Skip("CPU does not have PMULL")
+	}
+	field.HavePMULL = false
+	b.Cleanup(func() {
+		field.HavePMULL = true
+	})
+	benchmarkPolyval(b, nblocks)
+}
@@ -0,0 +1,61 @@
+//go:build riscv64 && gc && !purego && !noasm && !tinygo
+
+package polyval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+func disableAsm(t *testing.T) {
+	old := field.HaveAsm
+	t.Cleanup(func() {
+		field.HaveAsm = old
+	})
+	field.HaveAsm = false
+}
+
+func disableZvbc(t *testing.T) {
+	old := field.HaveZvbc
+	t.Cleanup(func() {
+		field.HaveZvbc = old
+	})
+	field.HaveZvbc = false
+}
+
+func runTests(t *testing.T, fn func(t *testing.T)) {
+	if field.HaveAsm {
+		t.Run("assembly", fn)
+		if field.HaveZvbc {
+			t.Run("assemblyNoZvbc", func(t *testing.T) {
+				disableZvbc(t)
+				fn(t)
+			})
+		}
+	}
+	t.Run("generic", func(t *testing.T) {
+		disableAsm(t)
+		fn(t)
+	})
+}
+
+func BenchmarkPolyvalNoZvbc(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkPolyvalNoZvbc(b, n)
+		})
+	}
+}
+
+func benchmarkPolyvalNoZvbc(b *testing.B, nblocks int) {
+	if !field.HaveZvbc {
+		b.Skip("CPU does not have Zvbc")
+	}
+	field.HaveZvbc = false
+	b.Cleanup(func() {
+		field.HaveZvbc = true
+	})
+	benchmarkPolyval(b, nblocks)
+}
@@ -4,15 +4,21 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ericlagergren/testutil"
 	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval/internal/field"
+	"github.com/ericlagergren/polyval/internal/gcm"
 )
 
 func unhex(s string) []byte {
@@ -23,26 +29,6 @@ func unhex(s string) []byte {
 	return p
 }
 
-// TestCtmulCommutative tests that ctmul is commutative,
-// a required property for multiplication.
-func TestCtmulCommutative(t *testing.T) {
-	runTests(t, testCtmulCommutative)
-}
-
-func testCtmulCommutative(t *testing.T) {
-	seed := uint64(time.Now().UnixNano())
-	rng := rand.New(rand.NewSource(seed))
-	for i := 0; i < 1e6; i++ {
-		x, y := rng.Uint64(), rng.Uint64()
-		xy1, xy0 := ctmul(x, y)
-		yx1, yx0 := ctmul(y, x)
-		if xy1 != yx1 || xy0 != yx0 {
-			t.Fatalf("%#0.16x*%#0.16x: (%#0.16x, %#0.16x) != (%#0.16x, %#0.16x)",
-				x, y, xy1, xy0, yx1, yx0)
-		}
-	}
-}
-
 // TestPolyvalRFCVectors tests polyval using test vectors from
 // RFC 8452.
 func TestPolyvalRFCVectors(t *testing.T) {
@@ -79,7 +65,7 @@ func testPolyvalRFCVectors(t *testing.T) {
 		p, _ := New(tc.H) // specialized
 		for _, x := range tc.X {
 			p.Update(x)
-			polymulBlocksGeneric(&g.y, &g.pow, x)
+			field.MulBlocksGeneric(&g.y, &g.pow, x)
 
 			blocks = append(blocks, x...)
 		}
@@ -102,7 +88,7 @@ func testPolyvalRFCVectors(t *testing.T) {
 		}
 
 		g.Reset()
-		polymulBlocksGeneric(&g.y, &g.pow, blocks)
+		field.MulBlocksGeneric(&g.y, &g.pow, blocks)
 		if got := g.Sum(nil); !bytes.Equal(got, want) {
 			t.Fatalf("#%d: expected %x, got %x", i, want, got)
 		}
@@ -140,10 +126,106 @@ func testMultiBlockUpdate(t *testing.T) {
 	}
 }
 
+// TestSumBatch tests that SumBatch matches Sum and does not
+// change the running hash.
+func TestSumBatch(t *testing.T) {
+	runTests(t, testSumBatch)
+}
+
+func testSumBatch(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+
+	msgs := make([][]byte, 100)
+	for i := range msgs {
+		msgs[i] = make([]byte, 16*rng.Intn(40))
+		rng.Read(msgs[i])
+	}
+	out := make([][Size]byte, len(msgs)+1)
+
+	p, _ := New(key)
+	p.Update(msgs[1])
+	want := p.Sum(nil)
+	p.SumBatch(msgs, out)
+	if got := p.Sum(nil); !bytes.Equal(got, want) {
+		t.Fatalf("running hash changed: expected %x, got %x", want, got)
+	}
+	for i, m := range msgs {
+		if want := Sum(key, m); out[i] != want {
+			t.Fatalf("#%d: expected %x, got %x", i, want, out[i])
+		}
+	}
+	if out[len(msgs)] != [Size]byte{} {
+		t.Fatalf("wrote past the last message: %x", out[len(msgs)])
+	}
+
+	for _, tc := range []struct {
+		msgs [][]byte
+		out  [][Size]byte
+	}{
+		{msgs, out[:len(msgs)-1]},
+		{[][]byte{make([]byte, 17)}, out},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			p.SumBatch(tc.msgs, tc.out)
+		}()
+	}
+}
+
+// TestUpdateGHASH tests that UpdateGHASH matches Update with
+// byte-reversed blocks and computes GHASH with a converted key.
+func TestUpdateGHASH(t *testing.T) {
+	runTests(t, testUpdateGHASH)
+}
+
+func testUpdateGHASH(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	buf := make([]byte, 16*200)
+
+	for _, nblocks := range []int{0, 1, 15, 16, 17, 63, 64, 65, 200} {
+		rng.Read(key)
+		key[0] |= 1
+		blocks := buf[:16*nblocks]
+		rng.Read(blocks)
+
+		want, _ := New(key)
+		for i := 0; i < len(blocks); i += 16 {
+			want.Update(byteRev(blocks[i : i+16]))
+		}
+		got, _ := New(key)
+		got.UpdateGHASH(blocks)
+		if !bytes.Equal(got.Sum(nil), want.Sum(nil)) {
+			t.Fatalf("%d blocks: expected %x, got %x",
+				nblocks, want.Sum(nil), got.Sum(nil))
+		}
+
+		g := gcm.New(key)
+		g.UpdateBlocks(blocks)
+		p, _ := New(mulx(byteRev(key)))
+		p.UpdateGHASH(blocks)
+		if got, want := byteRev(p.Sum(nil)), g.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("%d blocks: expected GHASH %x, got %x", nblocks, want, got)
+		}
+	}
+}
+
+//go:generate go run github.com/ericlagergren/polyval/internal/cmd/vectors -out testdata/polyval.json
+
 // TestPolyvalVectors tests polyval using the Google-provided
 // test vectors.
 //
 // See https://github.com/google/hctr2/blob/2a80dc7f742127b1f68f02b310975ac7928ae25e/test_vectors/ours/Polyval/Polyval.json
+// and internal/cmd/vectors, which imports them.
 func TestPolyvalVectors(t *testing.T) {
 	runTests(t, testPolyvalVectors)
 }
@@ -189,7 +271,7 @@ func testPolyvalVectors(t *testing.T) {
 
 		blocks := unhex(v.Input.Message)
 		p.Update(blocks)
-		polymulBlocksGeneric(&g.y, &g.pow, blocks)
+		field.MulBlocksGeneric(&g.y, &g.pow, blocks)
 
 		want := unhex(v.Hash)
 		if got := p.Sum(nil); !bytes.Equal(want, got) {
@@ -207,6 +289,283 @@ func testPolyvalVectors(t *testing.T) {
 	}
 }
 
+//go:generate go run github.com/ericlagergren/polyval/internal/cmd/wycheproof -out testdata/polyval_wycheproof.json
+
+// TestWycheproofVectors tests polyval using the vectors
+// exported by internal/cmd/wycheproof.
+func TestWycheproofVectors(t *testing.T) {
+	runTests(t, testWycheproofVectors)
+}
+
+func testWycheproofVectors(t *testing.T) {
+	var f struct {
+		Algorithm  string `json:"algorithm"`
+		TestGroups []struct {
+			Tests []struct {
+				TcID    int    `json:"tcId"`
+				Comment string `json:"comment"`
+				Key     string `json:"key"`
+				Msg     string `json:"msg"`
+				Tag     string `json:"tag"`
+				Result  string `json:"result"`
+			} `json:"tests"`
+		} `json:"testGroups"`
+	}
+	buf, err := os.ReadFile(filepath.Join("testdata", "polyval_wycheproof.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(buf, &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Algorithm != "POLYVAL" {
+		t.Fatalf("unexpected algorithm: %q", f.Algorithm)
+	}
+	for _, g := range f.TestGroups {
+		for _, v := range g.Tests {
+			key := unhex(v.Key)
+			p, err := New(key)
+			if err != nil {
+				t.Fatalf("#%d: (%s): %v", v.TcID, v.Comment, err)
+			}
+			p.Update(unhex(v.Msg))
+			got := p.Sum(nil)
+			if ok := bytes.Equal(got, unhex(v.Tag)); ok != (v.Result == "valid") {
+				t.Fatalf("#%d: (%s): %s tag %s, got %x",
+					v.TcID, v.Comment, v.Result, v.Tag, got)
+			}
+		}
+	}
+}
+
+// TestStatesEqual tests StatesEqual.
+func TestStatesEqual(t *testing.T) {
+	runTests(t, testStatesEqual)
+}
+
+func testStatesEqual(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	for i := 0; i < 1000; i++ {
+		rng.Read(key)
+		blocks := make([]byte, 16*(1+rng.Intn(32)))
+		rng.Read(blocks)
+
+		a, _ := New(key)
+		b, _ := New(key)
+		if !StatesEqual(a, b) {
+			t.Fatalf("#%d: new states should be equal", i)
+		}
+		a.Update(blocks)
+		if StatesEqual(a, b) {
+			t.Fatalf("#%d: states should differ after Update", i)
+		}
+		b.Update(blocks)
+		if !StatesEqual(a, b) {
+			t.Fatalf("#%d: states should be equal after Update", i)
+		}
+
+		// Change a single bit of the input.
+		a.Update(blocks)
+		blocks[rng.Intn(len(blocks))] ^= 1 << rng.Intn(8)
+		b.Update(blocks)
+		if StatesEqual(a, b) {
+			t.Fatalf("#%d: states should differ", i)
+		}
+
+		// Change a single bit of the key.
+		key[rng.Intn(len(key))] ^= 1 << rng.Intn(8)
+		d, err := New(key)
+		if err != nil {
+			continue
+		}
+		d.y = a.y
+		if StatesEqual(a, d) {
+			t.Fatalf("#%d: states with different keys should differ", i)
+		}
+	}
+}
+
+// TestWipe tests that Wipe zeroes the key material and running
+// hash.
+func TestWipe(t *testing.T) {
+	runTests(t, testWipe)
+}
+
+func testWipe(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262")
+
+	p, _ := New(key)
+	p.Update(msg)
+	p.Wipe()
+	if p.h != (field.Element{}) || p.y != (field.Element{}) {
+		t.Fatal("Wipe did not clear the key or state")
+	}
+	for i, x := range p.pow {
+		if x != (field.Element{}) {
+			t.Fatalf("Wipe did not clear pow[%d]", i)
+		}
+	}
+
+	c, _ := NewCompact(key)
+	c.Update(msg)
+	c.Wipe()
+	if c.h[0] != (field.Element{}) || c.y != (field.Element{}) {
+		t.Fatal("Wipe did not clear the key or state")
+	}
+}
+
+// TestNewFromReader tests that NewFromReader reads exactly one
+// key from its input.
+func TestNewFromReader(t *testing.T) {
+	runTests(t, testNewFromReader)
+}
+
+func testNewFromReader(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262")
+
+	r := bytes.NewReader(append(key, msg...))
+	p, err := NewFromReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != len(msg) {
+		t.Fatalf("expected %d unread bytes, got %d", len(msg), r.Len())
+	}
+	want, _ := New(key)
+	p.Update(msg)
+	want.Update(msg)
+	if got := p.Sum(nil); !bytes.Equal(got, want.Sum(nil)) {
+		t.Fatalf("expected %x, got %x", want.Sum(nil), got)
+	}
+
+	_, err = NewFromReader(bytes.NewReader(key[:15]))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	_, err = NewFromReader(bytes.NewReader(make([]byte, 16)))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// TestVerifySum tests VerifySum.
+func TestVerifySum(t *testing.T) {
+	runTests(t, testVerifySum)
+}
+
+func testVerifySum(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	for i := 0; i < 100; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		msg := make([]byte, 16*rng.Intn(40))
+		rng.Read(msg)
+		tag := Sum(key, msg)
+
+		if !VerifySum(key, msg, tag[:]) {
+			t.Fatalf("#%d: valid tag rejected", i)
+		}
+		j, bit := rng.Intn(len(tag)), byte(1)<<rng.Intn(8)
+		tag[j] ^= bit
+		if VerifySum(key, msg, tag[:]) {
+			t.Fatalf("#%d: modified tag accepted", i)
+		}
+		tag[j] ^= bit
+		if VerifySum(key, msg, tag[:15]) || VerifySum(key, msg, nil) {
+			t.Fatalf("#%d: short tag accepted", i)
+		}
+	}
+}
+
+//go:generate go run github.com/ericlagergren/polyval/internal/cmd/edgecases -out testdata/edgecases.json
+
+// TestEdgeCaseVectors tests polyval using the vectors generated
+// by internal/cmd/edgecases.
+func TestEdgeCaseVectors(t *testing.T) {
+	runTests(t, testEdgeCaseVectors)
+}
+
+func testEdgeCaseVectors(t *testing.T) {
+	var vecs []struct {
+		Comment string `json:"comment"`
+		Key     string `json:"key"`
+		Pattern string `json:"pattern"`
+		Length  int    `json:"length"`
+		Tag     string `json:"tag"`
+	}
+	buf, err := os.ReadFile(filepath.Join("testdata", "edgecases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(buf, &vecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vecs {
+		pattern := unhex(v.Pattern)
+		msg := make([]byte, 0, v.Length)
+		for len(msg) < v.Length {
+			msg = append(msg, pattern...)
+		}
+		msg = msg[:v.Length]
+
+		p, err := New(unhex(v.Key))
+		if err != nil {
+			t.Fatalf("#%d: (%s): %v", i, v.Comment, err)
+		}
+		p.Update(msg)
+		want := unhex(v.Tag)
+		if got := p.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: (%s): expected %x, got %x",
+				i, v.Comment, want, got)
+		}
+	}
+}
+
+// TestSumBatchConcurrent tests that SumBatch can be called from
+// multiple goroutines with the same Polyval, each with its own
+// messages and outputs.
+func TestSumBatchConcurrent(t *testing.T) {
+	runTests(t, testSumBatchConcurrent)
+}
+
+func testSumBatchConcurrent(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	p, _ := New(key)
+	p.Update(make([]byte, 16*3))
+	var wg sync.WaitGroup
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(uint64(i)))
+			msgs := make([][]byte, 8)
+			out := make([][Size]byte, len(msgs))
+			for j := 0; j < 100; j++ {
+				for k := range msgs {
+					msgs[k] = make([]byte, 16*rng.Intn(40))
+					rng.Read(msgs[k])
+				}
+				p.SumBatch(msgs, out)
+				for k, m := range msgs {
+					if want := Sum(key, m); out[k] != want {
+						t.Errorf("expected %x, got %x", want, out[k])
+						return
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
 // TestZeroKey tests that New rejects zero keys.
 func TestZeroKey(t *testing.T) {
 	runTests(t, testZeroKey)
@@ -246,6 +605,10 @@ func testMarshal(t *testing.T) {
 		// Save the current digest and state.
 		prevSum := h.Sum(nil)
 		prev, _ := h.MarshalBinary()
+		app, _ := h.AppendBinary([]byte("prefix"))
+		if !bytes.Equal(app, append([]byte("prefix"), prev...)) {
+			t.Fatalf("#%d: AppendBinary does not match MarshalBinary", i)
+		}
 
 		// Update the state and save the digest.
 		h.Update(blocks)
@@ -255,6 +618,9 @@ func testMarshal(t *testing.T) {
 		// same results.
 		var h2 Polyval
 		h2.UnmarshalBinary(prev)
+		if h2.pow != h.pow {
+			t.Fatalf("#%d: expected %v, got %v", i, h.pow, h2.pow)
+		}
 		if got := h2.Sum(nil); !bytes.Equal(got, prevSum) {
 			t.Fatalf("#%d: exepected %x, got %x", i, prevSum, got)
 		}
@@ -265,26 +631,95 @@ func testMarshal(t *testing.T) {
 	}
 }
 
+// TestAllocs tests that the steady-state API does not
+// allocate.
+func TestAllocs(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("coverage instrumentation may allocate")
+	}
+
+	key := unhex("01000000000000000000000000000000")
+	msg := make([]byte, 16*37)
+	var p Polyval
+	var cp Compact
+	cp.Init(key)
+	var sum [Size]byte
+	var state [marshaledSize]byte
+	msgs := [][]byte{msg, msg[:16]}
+	sums := make([][Size]byte, len(msgs))
+	f, _ := NewFastHash(key)
+	c := NewKeyCache(1)
+	c.Init(&p, key)
+
+	for _, tc := range []struct {
+		name string
+		fn   func()
+	}{
+		{"Init", func() { p.Init(key) }},
+		{"New", func() {
+			q, _ := New(key)
+			q.Update(msg)
+			q.Sum(sum[:0])
+		}},
+		{"NewCompact", func() {
+			q, _ := NewCompact(key)
+			q.Update(msg)
+			q.Sum(sum[:0])
+		}},
+		{"KeyCache.New", func() {
+			q, _ := c.New(key)
+			q.Update(msg)
+			q.Sum(sum[:0])
+		}},
+		{"Update+Sum", func() {
+			p.Update(msg)
+			p.Sum(sum[:0])
+		}},
+		{"UpdateGHASH", func() { p.UpdateGHASH(msg) }},
+		{"Compact.Update+Sum", func() {
+			cp.Update(msg)
+			cp.Sum(sum[:0])
+		}},
+		{"Sum", func() { sum = Sum(key, msg) }},
+		{"SumBatch", func() { p.SumBatch(msgs, sums) }},
+		{"AppendBinary", func() { p.AppendBinary(state[:0]) }},
+		{"UnmarshalBinary", func() { p.UnmarshalBinary(state[:]) }},
+		{"KeyCache.Init", func() { c.Init(&p, key) }},
+		{"Get+Put", func() {
+			q, _ := Get(key)
+			q.Update(msg)
+			byteSink = q.Sum(sum[:0])
+			Put(q)
+		}},
+		{"FastHash.Sum64", func() { f.Sum64(msg) }},
+		{"Hash64", func() { Hash64(msg) }},
+	} {
+		if n := testing.AllocsPerRun(100, tc.fn); n != 0 {
+			t.Errorf("%s: %.1f allocations", tc.name, n)
+		}
+	}
+}
+
 func TestInlining(t *testing.T) {
 	want := []string{
+		"New",
+		"NewCompact",
+		"NewFastHash",
+		"(*KeyCache).New",
 		"(*Polyval).BlockSize",
 		"(*Polyval).Reset",
 		"(*Polyval).Size",
 		"(*Polyval).Update",
-		"fieldElement.String",
 		"(*Polyval).MarshalBinary",
 		"(*Polyval).Sum",
-		"(*fieldElement).setBytes",
-	}
-	if runtime.GOARCH == "amd64" {
-		want = append(want, "ctmul")
+		"(*Polyval).Wipe",
+		"StatesEqual",
 	}
 	testutil.TestInlining(t, "github.com/ericlagergren/polyval", want...)
 }
 
 var (
-	byteSink  []byte
-	ctmulSink uint64
+	byteSink []byte
 )
 
 var benchBlocks = []int{
@@ -306,10 +741,67 @@ func BenchmarkPolyval(b *testing.B) {
 	}
 }
 
+func BenchmarkUpdateGHASH(b *testing.B) {
+	for _, n := range benchBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			b.SetBytes(int64(n) * 16)
+			p, _ := New(unhex("01000000000000000000000000000000"))
+			x := make([]byte, n*p.BlockSize())
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				p.UpdateGHASH(x)
+			}
+			byteSink = p.Sum(nil)
+		})
+	}
+}
+
+// benchOddBlocks are block counts that are not multiples of the
+// kernels' strides, so they exercise the remainder paths.
+var benchOddBlocks = []int{
+	7,   // 112
+	9,   // 144
+	15,  // 240
+	17,  // 272
+	23,  // 368
+	33,  // 528
+	65,  // 1040
+	129, // 2064
+}
+
+func BenchmarkPolyvalOdd(b *testing.B) {
+	for _, n := range benchOddBlocks {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkPolyval(b, n)
+		})
+	}
+}
+
+// BenchmarkPolyvalUnaligned measures inputs that do not start
+// on a 16-byte boundary.
+func BenchmarkPolyvalUnaligned(b *testing.B) {
+	for _, off := range []int{1, 8} {
+		for _, n := range []int{1, 9, 16, 65, 512} {
+			b.Run(fmt.Sprintf("offset%d/%d", off, n*16), func(b *testing.B) {
+				benchmarkPolyvalOffset(b, n, off)
+			})
+		}
+	}
+}
+
 func benchmarkPolyval(b *testing.B, nblocks int) {
+	benchmarkPolyvalOffset(b, nblocks, 0)
+}
+
+// benchmarkPolyvalOffset is like benchmarkPolyval, but the
+// input starts off bytes past a 16-byte boundary.
+func benchmarkPolyvalOffset(b *testing.B, nblocks, off int) {
 	b.SetBytes(int64(nblocks) * 16)
 	p, _ := New(unhex("01000000000000000000000000000000"))
-	x := make([]byte, nblocks*p.BlockSize())
+	// Large allocations are at least 16-byte aligned.
+	buf := make([]byte, off+nblocks*p.BlockSize()+16)
+	x := buf[off : off+nblocks*p.BlockSize()]
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -318,6 +810,30 @@ func benchmarkPolyval(b *testing.B, nblocks int) {
 	byteSink = p.Sum(nil)
 }
 
+func BenchmarkSumBatch(b *testing.B) {
+	for _, n := range benchBlocks[:4] {
+		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
+			benchmarkSumBatch(b, n)
+		})
+	}
+}
+
+func benchmarkSumBatch(b *testing.B, nblocks int) {
+	const batch = 64
+	b.SetBytes(int64(nblocks) * 16 * batch)
+	p, _ := New(unhex("01000000000000000000000000000000"))
+	msgs := make([][]byte, batch)
+	for i := range msgs {
+		msgs[i] = make([]byte, nblocks*p.BlockSize())
+	}
+	out := make([][Size]byte, batch)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		p.SumBatch(msgs, out)
+	}
+}
+
 func BenchmarkPolyvalGeneric(b *testing.B) {
 	for _, n := range benchBlocks {
 		b.Run(fmt.Sprintf("%d", n*16), func(b *testing.B) {
@@ -333,16 +849,7 @@ func benchmarkPolyvalGeneric(b *testing.B, nblocks int) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		polymulBlocksGeneric(&p.y, &p.pow, x)
+		field.MulBlocksGeneric(&p.y, &p.pow, x)
 	}
 	byteSink = p.Sum(nil)
 }
-
-func BenchmarkCtmul(b *testing.B) {
-	z1 := rand.Uint64()
-	z0 := rand.Uint64()
-	for i := 0; i < b.N; i++ {
-		z1, z0 = ctmul(z1, z0)
-	}
-	ctmulSink = z1 ^ z0
-}
@@ -0,0 +1,46 @@
+// Package polyvaltest helps test packages built on polyval.
+//
+// Package polyval picks one implementation at init, based on
+// the CPU's features. Packages built on it can use
+// ForEachImplementation to run their own tests with every
+// implementation the CPU supports, the same way package polyval
+// tests itself.
+package polyvaltest
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// Implementations returns the names of the implementations
+// supported by the CPU, most preferred first.
+//
+// The names are the same as those accepted by the
+// POLYVAL_KERNEL environment variable. The last is always
+// "generic".
+func Implementations() []string {
+	return field.Kernels()
+}
+
+// ForEachImplementation runs fn as a subtest with each
+// implementation supported by the CPU. Each subtest is named
+// after its implementation.
+//
+// The implementation is selected for the whole process, so
+// tests that call ForEachImplementation must not run in
+// parallel with other tests that use polyval. The original
+// implementation is restored when ForEachImplementation
+// returns.
+func ForEachImplementation(t *testing.T, fn func(t *testing.T)) {
+	t.Helper()
+
+	old := field.Kernel
+	defer field.UseKernel(old)
+	for _, name := range field.Kernels() {
+		if !field.UseKernel(name) {
+			t.Fatalf("polyvaltest: unable to select %q", name)
+		}
+		t.Run(name, fn)
+	}
+}
@@ -0,0 +1,48 @@
+package polyvaltest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ericlagergren/polyval"
+)
+
+// TestForEachImplementation tests that ForEachImplementation
+// selects each implementation in turn and restores the original
+// one.
+func TestForEachImplementation(t *testing.T) {
+	key, _ := hex.DecodeString("25629347589242761d31f826ba4b757b")
+	msg, _ := hex.DecodeString("4f4f95668c83dfb6401762bb2d01a262" +
+		"d1a24ddd2721d006bbe45f20d3c9f362")
+	want, _ := hex.DecodeString("f7a3b47b846119fae5b7866cf5e5b77e")
+
+	old := polyval.Features().Kernel
+	var seen []string
+	ForEachImplementation(t, func(t *testing.T) {
+		k := polyval.Features().Kernel
+		seen = append(seen, k)
+		if name := t.Name(); name != "TestForEachImplementation/"+k {
+			t.Fatalf("subtest %q ran with %q", name, k)
+		}
+		if got := polyval.Sum(key, msg); !bytes.Equal(got[:], want) {
+			t.Fatalf("expected %x, got %x", want, got)
+		}
+	})
+
+	impls := Implementations()
+	if len(seen) != len(impls) {
+		t.Fatalf("expected %q, got %q", impls, seen)
+	}
+	for i := range seen {
+		if seen[i] != impls[i] {
+			t.Fatalf("expected %q, got %q", impls, seen)
+		}
+	}
+	if impls[len(impls)-1] != "generic" {
+		t.Fatalf("expected generic last, got %q", impls)
+	}
+	if k := polyval.Features().Kernel; k != old {
+		t.Fatalf("expected %q to be restored, got %q", old, k)
+	}
+}
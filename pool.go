@@ -0,0 +1,37 @@
+package polyval
+
+import (
+	"sync"
+)
+
+var pool = sync.Pool{
+	New: func() interface{} {
+		return new(Polyval)
+	},
+}
+
+// Get returns a Polyval initialized with key from a shared
+// pool.
+//
+// It is like New, but reuses a Polyval returned to the pool by
+// Put, if any. This helps servers that hash many short messages
+// with Polyvals that escape to the heap. Get still computes the
+// powers of the key; see KeyCache for sharing them.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func Get(key []byte) (*Polyval, error) {
+	p := pool.Get().(*Polyval)
+	if err := p.Init(key); err != nil {
+		pool.Put(p)
+		return nil, err
+	}
+	return p, nil
+}
+
+// Put clears p and returns it to the pool used by Get.
+//
+// p must not be used after calling Put.
+func Put(p *Polyval) {
+	p.Wipe()
+	pool.Put(p)
+}
@@ -0,0 +1,48 @@
+package polyval
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestGetPut tests that Get matches New and that Put clears the
+// Polyval.
+func TestGetPut(t *testing.T) {
+	runTests(t, testGetPut)
+}
+
+func testGetPut(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	msg := make([]byte, 16*37)
+
+	for i := 0; i < 100; i++ {
+		rng.Read(key)
+		key[0] |= 1
+		rng.Read(msg)
+
+		want, _ := New(key)
+		want.Update(msg)
+
+		p, err := Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Update(msg)
+		if got := p.Sum(nil); !bytes.Equal(got, want.Sum(nil)) {
+			t.Fatalf("#%d: expected %x, got %x", i, want.Sum(nil), got)
+		}
+		Put(p)
+		if p.h != (Polyval{}).h || p.y != (Polyval{}).y || p.pow != (Polyval{}).pow {
+			t.Fatalf("#%d: Put did not clear the Polyval", i)
+		}
+	}
+
+	if _, err := Get(make([]byte, 16)); err == nil {
+		t.Fatal("expected an error")
+	}
+}
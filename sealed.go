@@ -0,0 +1,79 @@
+package polyval
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ericlagergren/subtle"
+)
+
+// sealedAD is the additional data for sealed states. It
+// separates them from anything else encrypted with the same
+// wrapping key and versions the encoding.
+const sealedAD = "polyval sealed state v1"
+
+// sealedSize is the size in bytes of the output of
+// MarshalBinarySealed.
+const sealedSize = 12 + marshaledSize + 16
+
+// errOpen is returned when a sealed state cannot be decrypted.
+var errOpen = errors.New("polyval: sealed state is corrupt or was sealed with a different key")
+
+// MarshalBinarySealed is like MarshalBinary, but encrypts and
+// authenticates the encoding with AES-GCM under wrappingKey.
+//
+// The encoding contains the POLYVAL key, so MarshalBinary's
+// output must be kept secret. The output of
+// MarshalBinarySealed can be stored or transmitted instead.
+//
+// wrappingKey must be 16, 24, or 32 bytes. Each call uses a
+// random nonce, so a wrapping key should seal no more than
+// 2^32 states.
+func (p *Polyval) MarshalBinarySealed(wrappingKey []byte) ([]byte, error) {
+	aead, err := newSealer(wrappingKey)
+	if err != nil {
+		return nil, err
+	}
+	var buf [marshaledSize]byte
+	defer subtle.Wipe(buf[:])
+	p.AppendBinary(buf[:0])
+
+	out := make([]byte, 12, sealedSize)
+	if _, err := rand.Read(out); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out, buf[:], []byte(sealedAD)), nil
+}
+
+// UnmarshalBinarySealed decrypts and decodes the output of
+// MarshalBinarySealed.
+//
+// It returns an error without changing p if data was not sealed
+// with wrappingKey or was modified.
+func (p *Polyval) UnmarshalBinarySealed(wrappingKey, data []byte) error {
+	aead, err := newSealer(wrappingKey)
+	if err != nil {
+		return err
+	}
+	if len(data) != sealedSize {
+		return fmt.Errorf("invalid data size: %d", len(data))
+	}
+	var buf [marshaledSize]byte
+	defer subtle.Wipe(buf[:])
+	if _, err := aead.Open(buf[:0], data[:12], data[12:], []byte(sealedAD)); err != nil {
+		return errOpen
+	}
+	return p.UnmarshalBinary(buf[:])
+}
+
+// newSealer returns the AEAD for MarshalBinarySealed.
+func newSealer(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapping key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
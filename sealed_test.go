@@ -0,0 +1,83 @@
+package polyval
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// TestMarshalBinarySealed tests that sealed states round trip
+// and that modified states are rejected.
+func TestMarshalBinarySealed(t *testing.T) {
+	runTests(t, testMarshalBinarySealed)
+}
+
+func testMarshalBinarySealed(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	key := make([]byte, 16)
+	rng.Read(key)
+	key[0] |= 1
+	wrap := make([]byte, 32)
+	rng.Read(wrap)
+	msg := make([]byte, 16*37)
+	rng.Read(msg)
+
+	p, _ := New(key)
+	p.Update(msg)
+	data, err := p.MarshalBinarySealed(wrap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, key) {
+		t.Fatal("sealed state contains the key")
+	}
+	data2, err := p.MarshalBinarySealed(wrap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(data, data2) {
+		t.Fatal("sealing twice produced the same output")
+	}
+
+	var q Polyval
+	if err := q.UnmarshalBinarySealed(wrap, data); err != nil {
+		t.Fatal(err)
+	}
+	p.Update(msg)
+	q.Update(msg)
+	if got, want := q.Sum(nil), p.Sum(nil); !bytes.Equal(got, want) {
+		t.Fatalf("expected %x, got %x", want, got)
+	}
+
+	// Every modification is rejected and leaves the state
+	// unchanged.
+	want := q.Sum(nil)
+	for i := range data {
+		data[i] ^= 1
+		if err := q.UnmarshalBinarySealed(wrap, data); err == nil {
+			t.Fatalf("byte %d: expected an error", i)
+		}
+		data[i] ^= 1
+	}
+	if got := q.Sum(nil); !bytes.Equal(got, want) {
+		t.Fatalf("expected %x, got %x", want, got)
+	}
+	wrap[0] ^= 1
+	if err := q.UnmarshalBinarySealed(wrap, data); err == nil {
+		t.Fatal("expected an error for the wrong key")
+	}
+	wrap[0] ^= 1
+	for _, d := range [][]byte{nil, data[:len(data)-1], append(data, 0)} {
+		if err := q.UnmarshalBinarySealed(wrap, d); err == nil {
+			t.Fatalf("%d bytes: expected an error", len(d))
+		}
+	}
+	for _, n := range []int{0, 15, 17, 33} {
+		if _, err := p.MarshalBinarySealed(make([]byte, n)); err == nil {
+			t.Fatalf("%d-byte wrapping key: expected an error", n)
+		}
+	}
+}
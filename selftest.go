@@ -0,0 +1,131 @@
+package polyval
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// FeatureSet describes the implementation selected at init.
+type FeatureSet struct {
+	// Kernel is the multiplication kernel in use, like
+	// "avx512", "pmull", or "generic".
+	Kernel string
+	// Failed lists the kernels supported by the CPU that
+	// failed a known-answer test at init. They are never
+	// used.
+	//
+	// It is normally empty. Some emulators and hypervisors
+	// misexecute carry-less multiplication instructions.
+	Failed []string
+}
+
+// Features returns the implementation selected at init.
+func Features() FeatureSet {
+	return FeatureSet{
+		Kernel: field.Kernel,
+		Failed: append([]string(nil), field.FailedKernels...),
+	}
+}
+
+// SelfTest runs known-answer tests against the active
+// implementation.
+//
+// It is intended for power-on self tests and for validating
+// unusual deployment targets like emulators. It is not needed
+// for normal use.
+func SelfTest() error {
+	for i, tc := range selfTests {
+		key := mustUnhex(tc.key)
+		msg := tc.msg()
+		want := mustUnhex(tc.want)
+
+		// One-shot.
+		if got := Sum(key, msg); !bytes.Equal(got[:], want) {
+			return fmt.Errorf("polyval: self test #%d failed: expected %x, got %x",
+				i, want, got)
+		}
+
+		// Block at a time.
+		p, err := New(key)
+		if err != nil {
+			return fmt.Errorf("polyval: self test #%d failed: %w", i, err)
+		}
+		for b := msg; len(b) > 0; b = b[16:] {
+			p.Update(b[:16])
+		}
+		if got := p.Sum(nil); !bytes.Equal(got, want) {
+			return fmt.Errorf("polyval: self test #%d failed: expected %x, got %x",
+				i, want, got)
+		}
+	}
+	return nil
+}
+
+var selfTests = []struct {
+	key  string
+	msg  func() []byte
+	want string
+}{
+	// RFC 8452 appendix A: POLYVAL(H, X_1)
+	{
+		key: "25629347589242761d31f826ba4b757b",
+		msg: func() []byte {
+			return mustUnhex("4f4f95668c83dfb6401762bb2d01a262")
+		},
+		want: "cedac64537ff50989c16011551086d77",
+	},
+	// RFC 8452 appendix A: POLYVAL(H, X_1, X_2)
+	{
+		key: "25629347589242761d31f826ba4b757b",
+		msg: func() []byte {
+			return mustUnhex("4f4f95668c83dfb6401762bb2d01a262" +
+				"d1a24ddd2721d006bbe45f20d3c9f362")
+		},
+		want: "f7a3b47b846119fae5b7866cf5e5b77e",
+	},
+	// Nine blocks: shorter than the 16-block stride of the
+	// assembly kernels, and one single block plus one 8-block
+	// stride in the generic code.
+	{
+		key:  "25629347589242761d31f826ba4b757b",
+		msg:  func() []byte { return countingBlocks(9) },
+		want: "519b8f059a5c749b6ca3e6d5661d79c5",
+	},
+	// 17 blocks: one single block plus one 16-block stride in
+	// the assembly kernels, or two 8-block strides in the
+	// generic code.
+	{
+		key:  "25629347589242761d31f826ba4b757b",
+		msg:  func() []byte { return countingBlocks(17) },
+		want: "d9422ca479e07b010a7c98bed937b734",
+	},
+	// 64 blocks: four 16-block strides with no remainder, which
+	// is the only vector here that runs the AVX-512 kernel for
+	// more than one stride.
+	{
+		key:  "25629347589242761d31f826ba4b757b",
+		msg:  func() []byte { return countingBlocks(64) },
+		want: "469b42ee6660b75f00d3d95ce12c4a06",
+	},
+}
+
+// countingBlocks returns n blocks containing the bytes 0, 1,
+// 2, and so on.
+func countingBlocks(n int) []byte {
+	b := make([]byte, 16*n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func mustUnhex(s string) []byte {
+	p, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
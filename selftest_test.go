@@ -0,0 +1,31 @@
+package polyval
+
+import (
+	"testing"
+)
+
+// TestSelfTest tests that SelfTest passes for each
+// implementation.
+func TestSelfTest(t *testing.T) {
+	runTests(t, testSelfTest)
+}
+
+func testSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestFeatures tests that Features reports a kernel that
+// passed its known-answer test.
+func TestFeatures(t *testing.T) {
+	f := Features()
+	if f.Kernel == "" {
+		t.Fatal("no kernel selected")
+	}
+	for _, k := range f.Failed {
+		if k == f.Kernel {
+			t.Fatalf("selected kernel %q failed its known-answer test", k)
+		}
+	}
+}
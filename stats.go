@@ -0,0 +1,50 @@
+package polyval
+
+import (
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// Stats describes the blocks written by this package since the
+// program started, not counting the self tests run at init.
+//
+// The counters are only maintained when the package is built
+// with the polyvalstats build tag, which adds an atomic update
+// to every call that writes blocks. Stats is meant for checking
+// in production that buffers are large enough to use the wide
+// kernels. It can be published with expvar:
+//
+//    expvar.Publish("polyval", expvar.Func(func() interface{} {
+//        return polyval.ReadStats()
+//    }))
+type Stats struct {
+	// Enabled reports whether the counters are maintained.
+	Enabled bool
+	// Kernel is the multiplication kernel in use. See
+	// FeatureSet.
+	Kernel string
+	// Calls is the number of times a kernel was called to
+	// write at least one block. For example, each Update and
+	// each message in SumBatch is one call.
+	Calls uint64
+	// Bytes is the number of bytes written.
+	Bytes uint64
+	// WideBlocks is the number of blocks written in whole
+	// multiples of 16 blocks per call, which is the stride of
+	// the widest kernels. It does not depend on the kernel in
+	// use: the generic kernel, for example, has a shorter
+	// stride, and the AVX-512 kernel is not used for inputs
+	// shorter than 16 blocks.
+	WideBlocks uint64
+}
+
+// ReadStats returns the current counters.
+func ReadStats() Stats {
+	calls, blocks, wide, ok := field.ReadStats()
+	return Stats{
+		Enabled:    ok,
+		Kernel:     field.Kernel,
+		Calls:      calls,
+		Bytes:      blocks * 16,
+		WideBlocks: wide,
+	}
+}
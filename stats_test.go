@@ -0,0 +1,32 @@
+package polyval
+
+import (
+	"testing"
+)
+
+// TestStats tests that ReadStats counts the blocks written by
+// Update.
+func TestStats(t *testing.T) {
+	s := ReadStats()
+	if s.Kernel != Features().Kernel {
+		t.Fatalf("expected kernel %q, got %q", Features().Kernel, s.Kernel)
+	}
+	if !s.Enabled {
+		if s != (Stats{Kernel: s.Kernel}) {
+			t.Fatalf("expected zero counters, got %+v", s)
+		}
+		t.Skip("built without the polyvalstats tag")
+	}
+
+	p, _ := New(unhex("01000000000000000000000000000000"))
+	p.Update(nil)
+	p.Update(make([]byte, 16*37))
+	got := ReadStats()
+	want := s
+	want.Calls++
+	want.Bytes += 16 * 37
+	want.WideBlocks += 32
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,75 @@
+package polyval
+
+import (
+	"errors"
+)
+
+// ErrFinalized is returned when a Strict is written to after
+// Sum.
+var ErrFinalized = errors.New("polyval: Update after Sum")
+
+// Strict is a Polyval that cannot be written to after Sum.
+//
+// Calling Update after Sum without first calling Reset returns
+// ErrFinalized and leaves the state unchanged. This catches
+// callers that keep writing to a hash whose tag they have
+// already used. Sum can still be called more than once.
+type Strict struct {
+	p Polyval
+	// done is set by Sum and cleared by Reset.
+	done bool
+}
+
+// NewStrict creates a Strict.
+//
+// The key must be exactly 16 bytes long and cannot be all zero.
+func NewStrict(key []byte) (*Strict, error) {
+	var s Strict
+	if err := s.p.Init(key); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Size returns the size of a POLYVAL digest.
+func (s *Strict) Size() int {
+	return Size
+}
+
+// BlockSize returns the size of a POLYVAL block.
+func (s *Strict) BlockSize() int {
+	return 16
+}
+
+// Reset sets the hash to its original state and allows Update
+// to be called again.
+func (s *Strict) Reset() {
+	s.p.Reset()
+	s.done = false
+}
+
+// Update writes one or more blocks to the running hash.
+//
+// It returns ErrFinalized if Sum has been called since the last
+// Reset.
+//
+// If len(block) is not divisible by BlockSize, Update will panic.
+func (s *Strict) Update(blocks []byte) error {
+	if len(blocks)%16 != 0 {
+		panic("polyval: invalid input length")
+	}
+	if s.done {
+		return ErrFinalized
+	}
+	s.p.Update(blocks)
+	return nil
+}
+
+// Sum appends the current hash to b and returns the resulting
+// slice.
+//
+// After Sum, Update returns ErrFinalized until Reset.
+func (s *Strict) Sum(b []byte) []byte {
+	s.done = true
+	return s.p.Sum(b)
+}
@@ -0,0 +1,48 @@
+package polyval
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestStrict tests that Strict rejects Update after Sum until
+// Reset.
+func TestStrict(t *testing.T) {
+	runTests(t, testStrict)
+}
+
+func testStrict(t *testing.T) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	msg := unhex("4f4f95668c83dfb6401762bb2d01a262" +
+		"d1a24ddd2721d006bbe45f20d3c9f362")
+	want := unhex("f7a3b47b846119fae5b7866cf5e5b77e")
+
+	s, err := NewStrict(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Update(msg[:16]); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if err := s.Update(msg[16:]); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := s.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+		if err := s.Update(msg); !errors.Is(err, ErrFinalized) {
+			t.Fatalf("#%d: expected ErrFinalized, got %v", i, err)
+		}
+		// Sum is unchanged and can be called again.
+		if got := s.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("#%d: expected %x, got %x", i, want, got)
+		}
+		s.Reset()
+	}
+
+	if _, err := NewStrict(make([]byte, 16)); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,83 @@
+package polyval
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// ErrWeakKey is returned by CheckWeakKey for keys of small
+// multiplicative order.
+var ErrWeakKey = errors.New("polyval: weak key")
+
+// groupPrimes are the prime factors of 2^128-1, the order of
+// the multiplicative group of GF(2^128). Each occurs once.
+var groupPrimes = [...]uint64{
+	3, 5, 17, 257, 641, 65537, 274177, 6700417, 67280421310721,
+}
+
+// CheckWeakKey returns ErrWeakKey if key is weak, or an error
+// if key is otherwise invalid.
+//
+// 2^128-1 is composite, so GF(2^128) has nonzero elements of
+// small multiplicative order. If the element that POLYVAL
+// multiplies by has order d, then h^d = 1 and swapping block i
+// with block i+d of a message does not change its hash. A key
+// is weak if that order is less than 2^64, which is far longer
+// than any message. Only about one in 2^64 random keys is weak.
+//
+// New does not reject weak keys. Callers that want to can call
+// CheckWeakKey first. It runs in time independent of the key
+// and performs about 1,700 field multiplications, so it is much
+// more expensive than New.
+func CheckWeakKey(key []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	// POLYVAL multiplies by h*x^-128. Field operations work
+	// on elements multiplied by x^128, so h represents that
+	// element and the field's multiplicative identity is
+	// represented by x^128 mod the polynomial.
+	var h field.Element
+	h.SetBytes(key)
+	one := field.Element{Lo: 1, Hi: 0xc200000000000000}
+
+	// The order of h is the product of each prime p where
+	// h^((2^128-1)/p) != 1.
+	var hi, lo uint64 = 0, 1
+	for _, p := range groupPrimes {
+		eHi, r := bits.Div64(0, ^uint64(0), p)
+		eLo, _ := bits.Div64(r, ^uint64(0), p)
+		x := exp(h, one, eHi, eLo)
+		d := (x.Lo ^ one.Lo) | (x.Hi ^ one.Hi)
+		isOne := isZero(d)
+		m := uint64(isOne)
+		m |= p &^ -m
+
+		var c uint64
+		c, lo = bits.Mul64(lo, m)
+		hi = hi*m + c
+	}
+	if hi == 0 {
+		return ErrWeakKey
+	}
+	return nil
+}
+
+// exp returns h^e, where one represents the multiplicative
+// identity.
+//
+// e is public, so exp does not hide it.
+func exp(h, one field.Element, eHi, eLo uint64) field.Element {
+	z := one
+	for _, w := range [2]uint64{eHi, eLo} {
+		for i := 63; i >= 0; i-- {
+			field.Square(&z)
+			if (w>>i)&1 != 0 {
+				field.Mul(&z, &h)
+			}
+		}
+	}
+	return z
+}
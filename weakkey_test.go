@@ -0,0 +1,104 @@
+package polyval
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/ericlagergren/polyval/internal/field"
+)
+
+// TestCheckWeakKey tests CheckWeakKey with keys of known order.
+func TestCheckWeakKey(t *testing.T) {
+	runTests(t, testCheckWeakKey)
+}
+
+func testCheckWeakKey(t *testing.T) {
+	one := field.Element{Lo: 1, Hi: 0xc200000000000000}
+	key := func(x field.Element) []byte {
+		b := make([]byte, 16)
+		binary.LittleEndian.PutUint64(b[0:8], x.Lo)
+		binary.LittleEndian.PutUint64(b[8:16], x.Hi)
+		return b
+	}
+
+	// one is the identity.
+	seed := uint64(time.Now().UnixNano())
+	rng := rand.New(rand.NewSource(seed))
+	g := field.Element{Lo: rng.Uint64(), Hi: rng.Uint64()}
+	x := g
+	field.Mul(&x, &one)
+	if x != g {
+		t.Fatalf("%v is not the identity", one)
+	}
+	if err := CheckWeakKey(key(one)); !errors.Is(err, ErrWeakKey) {
+		t.Fatalf("identity: expected ErrWeakKey, got %v", err)
+	}
+
+	// Raise g to (2^128-1)/d to get an element whose order
+	// divides d.
+	pow := func(d uint64) field.Element {
+		eHi, r := bits.Div64(0, ^uint64(0), d)
+		eLo, _ := bits.Div64(r, ^uint64(0), d)
+		return exp(g, one, eHi, eLo)
+	}
+	for _, tc := range []struct {
+		d    uint64
+		weak bool
+	}{
+		{3, true},
+		{65537, true},
+		{274177, true},
+		{641 * 6700417, true},
+		{67280421310721, true},
+		{3 * 67280421310721, true},
+	} {
+		x := pow(tc.d)
+		if x == one {
+			// The order of g is not a multiple of d; this
+			// is unlikely.
+			continue
+		}
+		err := CheckWeakKey(key(x))
+		if tc.weak && !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("order %d: expected ErrWeakKey, got %v", tc.d, err)
+		}
+		if !tc.weak && err != nil {
+			t.Fatalf("order %d: unexpected error: %v", tc.d, err)
+		}
+	}
+
+	// g^(2^64-1) has order dividing 2^64+1 = 274177 *
+	// 67280421310721.
+	x = exp(g, one, 0, ^uint64(0))
+	if exp(x, one, 0, 274177) != one && exp(x, one, 0, 67280421310721) != one {
+		if err := CheckWeakKey(key(x)); err != nil {
+			t.Fatalf("order 2^64+1: unexpected error: %v", err)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		b := make([]byte, 16)
+		rng.Read(b)
+		b[0] |= 1
+		if err := CheckWeakKey(b); err != nil {
+			t.Fatalf("%x: unexpected error: %v", b, err)
+		}
+	}
+	if err := CheckWeakKey(make([]byte, 16)); err == nil || errors.Is(err, ErrWeakKey) {
+		t.Fatalf("zero key: expected an invalid key error, got %v", err)
+	}
+}
+
+func BenchmarkCheckWeakKey(b *testing.B) {
+	key := unhex("25629347589242761d31f826ba4b757b")
+	for i := 0; i < b.N; i++ {
+		if err := CheckWeakKey(key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}